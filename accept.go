@@ -0,0 +1,128 @@
+package toolkit
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// acceptSpec representa um item de um cabeçalho Accept ou Accept-Encoding,
+// com o valor normalizado e o seu fator de qualidade (q).
+type acceptSpec struct {
+	value string
+	q     float64
+}
+
+// parseAccept interpreta cabeçalhos no formato "valor;q=0.8, outro" e retorna
+// os itens ordenados do mais para o menos preferido. Itens com q inválido são
+// ignorados; a ordem original é mantida entre itens com o mesmo q.
+func parseAccept(header string) []acceptSpec {
+	var specs []acceptSpec
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		value, params, _ := strings.Cut(part, ";")
+		spec := acceptSpec{value: strings.ToLower(strings.TrimSpace(value)), q: 1}
+
+		valid := true
+		for _, param := range strings.Split(params, ";") {
+			key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			spec.q = q
+		}
+
+		if valid && spec.value != "" {
+			specs = append(specs, spec)
+		}
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specs[i].q > specs[j].q
+	})
+
+	return specs
+}
+
+// encodingQuality retorna o fator de qualidade que o cliente atribui a uma
+// codificação de conteúdo, considerando o curinga "*". Zero significa que a
+// codificação não é aceita.
+func encodingQuality(specs []acceptSpec, encoding string) float64 {
+	wildcard := -1.0
+	for _, spec := range specs {
+		switch spec.value {
+		case encoding:
+			return spec.q
+		case "*":
+			wildcard = spec.q
+		}
+	}
+	if wildcard >= 0 {
+		return wildcard
+	}
+	return 0
+}
+
+// defaultCompressibleTypes lista os tipos de conteúdo que valem a pena ser
+// comprimidos quando Tools.CompressibleTypes não é informado.
+var defaultCompressibleTypes = []string{
+	"text/*",
+	"application/json",
+	"application/problem+json",
+	"application/x-ndjson",
+	"application/javascript",
+	"application/xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// isCompressibleType verifica se o tipo de conteúdo informado consta na lista
+// de tipos compressíveis. Entradas terminadas em "/*" casam com qualquer subtipo.
+func (t *Tools) isCompressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	allowed := t.CompressibleTypes
+	if len(allowed) == 0 {
+		allowed = defaultCompressibleTypes
+	}
+
+	for _, candidate := range allowed {
+		candidate = strings.ToLower(candidate)
+		if prefix, ok := strings.CutSuffix(candidate, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if mediaType == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary acrescenta um valor ao cabeçalho Vary sem duplicá-lo.
+func addVary(h http.Header, value string) {
+	for _, existing := range h.Values("Vary") {
+		for _, v := range strings.Split(existing, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
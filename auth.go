@@ -0,0 +1,193 @@
+package toolkit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator adiciona credenciais a uma chamada remota. É chamado a cada
+// tentativa, logo antes do envio, permitindo renovar tokens e assinaturas.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc permite usar uma função comum como Authenticator.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken envia um token estático no cabeçalho Authorization.
+type BearerToken string
+
+func (b BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(b))
+	return nil
+}
+
+// Cabeçalhos e prefixo padrão das assinaturas HMAC de webhooks.
+const (
+	defaultSignatureHeader = "X-Signature"
+	defaultTimestampHeader = "X-Signature-Timestamp"
+	defaultSignaturePrefix = "sha256="
+)
+
+// HMACSigner assina o corpo das chamadas com HMAC-SHA256, no estilo dos
+// webhooks do Stripe e do GitHub. A assinatura cobre "timestamp.corpo" e é
+// enviada em SignatureHeader ("X-Signature" por padrão) como "sha256=<hex>",
+// com o timestamp Unix em TimestampHeader ("X-Signature-Timestamp" por
+// padrão). WebhookVerifier valida as assinaturas geradas com o mesmo segredo.
+type HMACSigner struct {
+	Secret          []byte
+	SignatureHeader string
+	TimestampHeader string
+}
+
+func (s *HMACSigner) Authenticate(req *http.Request) error {
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	} else if req.Body != nil && req.Body != http.NoBody {
+		return fmt.Errorf("hmac signer: request body cannot be read without consuming it")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(headerOrDefault(s.TimestampHeader, defaultTimestampHeader), timestamp)
+	req.Header.Set(headerOrDefault(s.SignatureHeader, defaultSignatureHeader), defaultSignaturePrefix+signPayload(s.Secret, timestamp, body))
+	return nil
+}
+
+// signPayload retorna o HMAC-SHA256 em hexadecimal de "timestamp.body".
+func signPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func headerOrDefault(header, def string) string {
+	if header == "" {
+		return def
+	}
+	return header
+}
+
+// OAuth2ClientCredentials obtém tokens de acesso pelo fluxo client
+// credentials do OAuth 2.0 (RFC 6749, seção 4.4) e os envia no cabeçalho
+// Authorization. O token fica em cache até pouco antes de expirar e é
+// renovado automaticamente; uma resposta 401 descarta o token em cache.
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// HTTPClient é usado para obter os tokens. Se nil, é usado um cliente com
+	// o timeout padrão das chamadas remotas.
+	HTTPClient *http.Client
+
+	// MaxResponseSize limita o corpo da resposta do TokenURL, como
+	// MaxRemoteResponseSize (10 MB por padrão).
+	MaxResponseSize int
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// tokenExpiryLeeway antecipa a renovação do token para evitar o uso de tokens
+// prestes a expirar.
+const tokenExpiryLeeway = 30 * time.Second
+
+func (o *OAuth2ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := o.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token retorna o token em cache ou obtém um novo no TokenURL.
+func (o *OAuth2ClientCredentials) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != "" && (o.expiry.IsZero() || time.Now().Before(o.expiry.Add(-tokenExpiryLeeway))) {
+		return o.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+
+	client := o.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultRemoteTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _, _ := readErrorBody(resp, 1024)
+		return "", fmt.Errorf("oauth2: token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var res struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	limits := Tools{MaxRemoteResponseSize: o.MaxResponseSize}
+	if err := limits.decodeRemoteResponse(resp, &res); err != nil {
+		return "", fmt.Errorf("oauth2: invalid token response: %w", err)
+	}
+	if res.AccessToken == "" {
+		return "", fmt.Errorf("oauth2: token response has no access_token")
+	}
+
+	o.token = res.AccessToken
+	o.expiry = time.Time{}
+	if res.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	return o.token, nil
+}
+
+// Invalidate descarta o token em cache, forçando a obtenção de um novo.
+func (o *OAuth2ClientCredentials) Invalidate() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token = ""
+}
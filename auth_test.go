@@ -0,0 +1,156 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTools_RemoteAuthenticator_BearerToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = io.WriteString(w, `{"error": false}`)
+	}))
+	defer server.Close()
+
+	testTools := Tools{RemoteAuthenticator: BearerToken("segredo")}
+	if _, _, err := testTools.PushJSONToRemote(server.URL, map[string]string{"foo": "bar"}); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if authorization != "Bearer segredo" {
+		t.Errorf("cabeçalho Authorization incorreto: %q", authorization)
+	}
+}
+
+func TestHMACSigner(t *testing.T) {
+	secret := []byte("segredo")
+	var verified bool
+	var received string
+
+	var serverTools Tools
+	handler := serverTools.VerifyWebhook(&WebhookVerifier{Secrets: [][]byte{secret}}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified = true
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		_, _ = io.WriteString(w, `{"error": false}`)
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	t.Run("assinatura aceita pelo verificador", func(t *testing.T) {
+		testTools := Tools{RemoteAuthenticator: &HMACSigner{Secret: secret}}
+		_, status, err := testTools.PushJSONToRemote(server.URL, map[string]string{"foo": "bar"})
+		if err != nil || status != http.StatusOK {
+			t.Fatalf("esperado 200, mas obteve %d: %v", status, err)
+		}
+		if !verified || received != `{"foo":"bar"}` {
+			t.Errorf("o handler deveria receber o corpo original: %q", received)
+		}
+	})
+
+	t.Run("segredo incorreto", func(t *testing.T) {
+		verified = false
+		testTools := Tools{RemoteAuthenticator: &HMACSigner{Secret: []byte("outro")}}
+		_, status, _ := testTools.PushJSONToRemote(server.URL, map[string]string{"foo": "bar"})
+		if status != http.StatusUnauthorized || verified {
+			t.Errorf("esperado 401, mas obteve %d", status)
+		}
+	})
+
+	t.Run("assinado novamente a cada tentativa", func(t *testing.T) {
+		var calls atomic.Int32
+		retryServer := httptest.NewServer(serverTools.VerifyWebhook(&WebhookVerifier{Secrets: [][]byte{secret}}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			_, _ = io.WriteString(w, `{"error": false}`)
+		})))
+		defer retryServer.Close()
+
+		testTools := Tools{
+			RemoteAuthenticator: &HMACSigner{Secret: secret},
+			RetryPolicy:         &RetryPolicy{MaxAttempts: 2, BaseDelay: 1},
+		}
+		ctx := WithIdempotencyKey(context.Background(), "chave")
+		_, status, err := testTools.PushJSONToRemoteContext(ctx, retryServer.URL, map[string]string{"foo": "bar"})
+		if err != nil || status != http.StatusOK || calls.Load() != 2 {
+			t.Errorf("a segunda tentativa deveria ser aceita: %d %v (%d chamadas)", status, err, calls.Load())
+		}
+	})
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "cliente" || pass != "senha" || r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "a b" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error": "invalid_client"}`)
+			return
+		}
+		n := tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token": "token-`+string(rune('0'+n))+`", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	var reject atomic.Bool
+	var lastAuthorization atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastAuthorization.Store(r.Header.Get("Authorization"))
+		if reject.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"ok": true}`)
+	}))
+	defer api.Close()
+
+	var testTools Tools
+	client := testTools.NewJSONClient(api.URL)
+	client.Authenticator = &OAuth2ClientCredentials{TokenURL: tokenServer.URL, ClientID: "cliente", ClientSecret: "senha", Scopes: []string{"a", "b"}}
+
+	for range 2 {
+		if _, err := GetJSON[map[string]bool](context.Background(), client, "/"); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if tokenRequests.Load() != 1 || lastAuthorization.Load() != "Bearer token-1" {
+		t.Errorf("o token deveria ser reutilizado: %d pedidos, %v", tokenRequests.Load(), lastAuthorization.Load())
+	}
+
+	reject.Store(true)
+	_, _ = GetJSON[map[string]bool](context.Background(), client, "/")
+	reject.Store(false)
+	if _, err := GetJSON[map[string]bool](context.Background(), client, "/"); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if tokenRequests.Load() != 2 || lastAuthorization.Load() != "Bearer token-2" {
+		t.Errorf("um 401 deveria forçar um novo token: %d pedidos, %v", tokenRequests.Load(), lastAuthorization.Load())
+	}
+
+	t.Run("credenciais recusadas", func(t *testing.T) {
+		auth := &OAuth2ClientCredentials{TokenURL: tokenServer.URL, ClientID: "cliente", ClientSecret: "errada"}
+		if _, err := auth.Token(context.Background()); err == nil {
+			t.Error("esperado erro ao obter o token")
+		}
+	})
+
+	t.Run("resposta muito grande", func(t *testing.T) {
+		large := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, `{"access_token": "`+strings.Repeat("a", 2048)+`"}`)
+		}))
+		defer large.Close()
+
+		auth := &OAuth2ClientCredentials{TokenURL: large.URL, MaxResponseSize: 1024}
+		if _, err := auth.Token(context.Background()); !errors.Is(err, ErrRemoteResponseTooLarge) {
+			t.Errorf("esperado ErrRemoteResponseTooLarge, mas obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,388 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MsgPackEncoder serializa respostas em MessagePack (application/msgpack).
+// Não faz parte dos Encoders padrão; para habilitá-lo, inclua-o em Tools.Encoders.
+// Structs seguem as mesmas tags `json` usadas por WriteJSON.
+type MsgPackEncoder struct{}
+
+func (MsgPackEncoder) ContentType() string { return "application/msgpack" }
+
+func (MsgPackEncoder) Encode(w io.Writer, v interface{}) error {
+	var buf bytes.Buffer
+	if err := encodeCompact(&msgpackWriter{buf: &buf}, reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// CBOREncoder serializa respostas em CBOR (application/cbor, RFC 8949).
+// Não faz parte dos Encoders padrão; para habilitá-lo, inclua-o em Tools.Encoders.
+// Structs seguem as mesmas tags `json` usadas por WriteJSON.
+type CBOREncoder struct{}
+
+func (CBOREncoder) ContentType() string { return "application/cbor" }
+
+func (CBOREncoder) Encode(w io.Writer, v interface{}) error {
+	var buf bytes.Buffer
+	if err := encodeCompact(&cborWriter{buf: &buf}, reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// compactWriter abstrai os formatos binários suportados por encodeCompact.
+type compactWriter interface {
+	writeNil()
+	writeBool(b bool)
+	writeInt(i int64)
+	writeUint(u uint64)
+	writeFloat(f float64)
+	writeString(s string)
+	writeBytes(b []byte)
+	writeArrayHeader(n int)
+	writeMapHeader(n int)
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// encodeCompact percorre v por reflexão e o escreve no formato de cw.
+func encodeCompact(cw compactWriter, v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			cw.writeNil()
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		cw.writeNil()
+		return nil
+	}
+
+	if v.Type() == timeType {
+		cw.writeString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return nil
+	}
+	if v.Type().Implements(textMarshalerType) && v.CanInterface() {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		cw.writeString(string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		cw.writeBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		cw.writeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		cw.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		cw.writeFloat(v.Float())
+	case reflect.String:
+		cw.writeString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			cw.writeNil()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			cw.writeBytes(b)
+			return nil
+		}
+		cw.writeArrayHeader(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeCompact(cw, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			cw.writeNil()
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		cw.writeMapHeader(len(keys))
+		for _, key := range keys {
+			if err := encodeCompact(cw, key); err != nil {
+				return err
+			}
+			if err := encodeCompact(cw, v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields := compactFields(v)
+		cw.writeMapHeader(len(fields))
+		for _, f := range fields {
+			cw.writeString(f.name)
+			if err := encodeCompact(cw, f.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+type compactField struct {
+	name  string
+	value reflect.Value
+}
+
+// compactFields lista os campos exportados de uma struct conforme as tags
+// `json`, promovendo campos de structs embutidas e respeitando omitempty.
+func compactFields(v reflect.Value) []compactField {
+	var fields []compactField
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name, ok := jsonFieldName(sf)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && sf.Tag.Get("json") == "" {
+			inner := fv
+			if inner.Kind() == reflect.Pointer {
+				if inner.IsNil() {
+					continue
+				}
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				fields = append(fields, compactFields(inner)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		_, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(fv) {
+			continue
+		}
+		fields = append(fields, compactField{name: name, value: fv})
+	}
+	return fields
+}
+
+// isEmptyValue segue a mesma definição de "vazio" usada por encoding/json no omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// msgpackWriter escreve valores no formato MessagePack.
+type msgpackWriter struct {
+	buf *bytes.Buffer
+}
+
+func (m *msgpackWriter) writeNil() { m.buf.WriteByte(0xc0) }
+
+func (m *msgpackWriter) writeBool(b bool) {
+	if b {
+		m.buf.WriteByte(0xc3)
+	} else {
+		m.buf.WriteByte(0xc2)
+	}
+}
+
+func (m *msgpackWriter) writeInt(i int64) {
+	switch {
+	case i >= 0:
+		m.writeUint(uint64(i))
+	case i >= -32:
+		m.buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8:
+		m.buf.Write([]byte{0xd0, byte(int8(i))})
+	case i >= math.MinInt16:
+		m.buf.WriteByte(0xd1)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(int16(i))))
+	case i >= math.MinInt32:
+		m.buf.WriteByte(0xd2)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(i))))
+	default:
+		m.buf.WriteByte(0xd3)
+		m.buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+}
+
+func (m *msgpackWriter) writeUint(u uint64) {
+	switch {
+	case u < 128:
+		m.buf.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		m.buf.Write([]byte{0xcc, byte(u)})
+	case u <= math.MaxUint16:
+		m.buf.WriteByte(0xcd)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(u)))
+	case u <= math.MaxUint32:
+		m.buf.WriteByte(0xce)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(u)))
+	default:
+		m.buf.WriteByte(0xcf)
+		m.buf.Write(binary.BigEndian.AppendUint64(nil, u))
+	}
+}
+
+func (m *msgpackWriter) writeFloat(f float64) {
+	m.buf.WriteByte(0xcb)
+	m.buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+}
+
+func (m *msgpackWriter) writeString(s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		m.buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		m.buf.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		m.buf.WriteByte(0xda)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		m.buf.WriteByte(0xdb)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	m.buf.WriteString(s)
+}
+
+func (m *msgpackWriter) writeBytes(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		m.buf.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		m.buf.WriteByte(0xc5)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		m.buf.WriteByte(0xc6)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	m.buf.Write(b)
+}
+
+func (m *msgpackWriter) writeArrayHeader(n int) {
+	switch {
+	case n < 16:
+		m.buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		m.buf.WriteByte(0xdc)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		m.buf.WriteByte(0xdd)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func (m *msgpackWriter) writeMapHeader(n int) {
+	switch {
+	case n < 16:
+		m.buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		m.buf.WriteByte(0xde)
+		m.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		m.buf.WriteByte(0xdf)
+		m.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// cborWriter escreve valores no formato CBOR.
+type cborWriter struct {
+	buf *bytes.Buffer
+}
+
+// writeHead escreve o cabeçalho de um item CBOR com o tipo principal e o argumento n.
+func (c *cborWriter) writeHead(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		c.buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		c.buf.Write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		c.buf.WriteByte(major | 25)
+		c.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		c.buf.WriteByte(major | 26)
+		c.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		c.buf.WriteByte(major | 27)
+		c.buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+func (c *cborWriter) writeNil() { c.buf.WriteByte(0xf6) }
+
+func (c *cborWriter) writeBool(b bool) {
+	if b {
+		c.buf.WriteByte(0xf5)
+	} else {
+		c.buf.WriteByte(0xf4)
+	}
+}
+
+func (c *cborWriter) writeInt(i int64) {
+	if i >= 0 {
+		c.writeHead(0, uint64(i))
+		return
+	}
+	c.writeHead(1, uint64(-1-i))
+}
+
+func (c *cborWriter) writeUint(u uint64) { c.writeHead(0, u) }
+
+func (c *cborWriter) writeFloat(f float64) {
+	c.buf.WriteByte(0xfb)
+	c.buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+}
+
+func (c *cborWriter) writeString(s string) {
+	c.writeHead(3, uint64(len(s)))
+	c.buf.WriteString(s)
+}
+
+func (c *cborWriter) writeBytes(b []byte) {
+	c.writeHead(2, uint64(len(b)))
+	c.buf.Write(b)
+}
+
+func (c *cborWriter) writeArrayHeader(n int) { c.writeHead(4, uint64(n)) }
+
+func (c *cborWriter) writeMapHeader(n int) { c.writeHead(5, uint64(n)) }
@@ -0,0 +1,489 @@
+package toolkit
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadQuery preenche data, um ponteiro para struct, com os parâmetros da query
+// string e os parâmetros de caminho (r.PathValue, Go 1.22+). Cada campo usa o
+// nome da tag `query` (ou da tag `json`, se ausente); campos com a tag `path`
+// são lidos de r.PathValue. A tag `default` define o valor usado quando o
+// parâmetro não é enviado e a tag `layout` define o formato de campos
+// time.Time (RFC 3339 por padrão). Listas aceitam parâmetros repetidos
+// (?tag=a&tag=b) ou separados por vírgula (?tag=a,b).
+//
+//	type ListParams struct {
+//		ID     int       `path:"id"`
+//		Status string    `query:"status" default:"open" validate:"oneof=open closed"`
+//		Tags   []string  `query:"tag"`
+//		Since  time.Time `query:"since" layout:"2006-01-02"`
+//	}
+//
+// Após o preenchimento, data é validado com as mesmas regras e o mesmo formato
+// de erros (ValidationErrors) de Validate.
+func (t *Tools) ReadQuery(r *http.Request, data interface{}) error {
+	query := r.URL.Query()
+
+	b := &binder{
+		tag:         "query",
+		values:      query,
+		splitCommas: true,
+		pathValue:   r.PathValue,
+	}
+	return b.bind(data)
+}
+
+// ReadForm preenche data, um ponteiro para struct, com os valores de um corpo
+// application/x-www-form-urlencoded ou multipart/form-data. Cada campo usa o
+// nome da tag `form` (ou da tag `json`, se ausente) e aceita os mesmos tipos e
+// tags de ReadQuery. Campos aninhados usam a notação "endereco.rua" e listas
+// de structs usam índices contíguos a partir de 0, como "itens[0].nome";
+// índices com lacunas são recusados.
+//
+// Campos do tipo *UploadedFile ou []*UploadedFile recebem os arquivos enviados
+// com o mesmo nome, gravados em uploadDir com as mesmas regras de UploadFiles
+// (AllowedTypes e renomeação). Se a leitura ou a validação falhar, os arquivos
+// já gravados são removidos. O corpo é limitado a MaxFileSize em formulários
+// multipart e a MaxJSONSize nos demais, com as mesmas mensagens de ReadJSON.
+func (t *Tools) ReadForm(w http.ResponseWriter, r *http.Request, data interface{}, uploadDir ...string) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return newRequestError(ErrUnsupportedMediaType, "unsupported content type %q", r.Header.Get("Content-Type"))
+	}
+
+	var files map[string][]*multipart.FileHeader
+	switch mediaType {
+	case "multipart/form-data":
+		maxBytes := t.MaxFileSize
+		if maxBytes == 0 {
+			maxBytes = 1024 * 1024 * 10 // 10 MB default
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+		if err := r.ParseMultipartForm(int64(maxBytes)); err != nil {
+			return formParseError(err, maxBytes)
+		}
+		files = r.MultipartForm.File
+	case "application/x-www-form-urlencoded":
+		maxBytes := t.maxJSONBytes()
+		if err := t.limitRequestBody(w, r, maxBytes); err != nil {
+			return err
+		}
+		if err := r.ParseForm(); err != nil {
+			return formParseError(err, maxBytes)
+		}
+	default:
+		return newRequestError(ErrUnsupportedMediaType, "unsupported content type %q", mediaType)
+	}
+
+	var saved []string
+	b := &binder{
+		tag:    "form",
+		values: r.PostForm,
+		files:  files,
+		saveFile: func(hdr *multipart.FileHeader) (*UploadedFile, error) {
+			if len(uploadDir) == 0 {
+				return nil, errors.New("internal error: uploadDir is required to receive files")
+			}
+			file, err := t.processUploadedFile(hdr, uploadDir[0], true)
+			if err == nil {
+				saved = append(saved, filepath.Join(uploadDir[0], file.NewFileName))
+			}
+			return file, err
+		},
+	}
+	if err := b.bind(data); err != nil {
+		// Sem sucesso, os arquivos já gravados não seriam encontrados pelo chamador.
+		for _, path := range saved {
+			_ = os.Remove(path)
+		}
+		return err
+	}
+	return nil
+}
+
+// formParseError converte erros de leitura de formulários nas mensagens do toolkit.
+func formParseError(err error, maxBytes int) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return newRequestError(ErrBodyTooLarge, "body must not be larger than %d bytes", maxBytes)
+	}
+	return newRequestError(ErrMalformedBody, "body contains badly-formed form data: %v", err)
+}
+
+// binder preenche structs a partir de pares chave/valor, como query strings e
+// formulários. Campos aninhados usam a notação "endereco.rua" e listas de
+// structs usam índices contíguos a partir de 0, como "itens[0].nome".
+type binder struct {
+	tag         string
+	values      map[string][]string
+	splitCommas bool
+	pathValue   func(name string) string
+	files       map[string][]*multipart.FileHeader
+	saveFile    func(hdr *multipart.FileHeader) (*UploadedFile, error)
+
+	errs       ValidationErrors
+	work       int
+	tooComplex bool
+}
+
+// bind preenche data e executa a validação, retornando os erros agregados.
+func (b *binder) bind(data interface{}) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("internal error: %s target must be a non-nil pointer to a struct", b.tag)
+	}
+
+	b.bindStruct(v.Elem(), "", "")
+	if b.tooComplex {
+		return newRequestError(ErrMalformedBody, "%s contains too many nested fields or indexes", b.tag)
+	}
+	if len(b.errs) > 0 {
+		return b.errs
+	}
+	return validateWith(data, b.fieldName)
+}
+
+// fieldName retorna o nome do campo conforme a tag do binder, a tag `path` ou a tag `json`.
+func (b *binder) fieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get(b.tag)
+	if tag == "" {
+		tag = sf.Tag.Get("path")
+	}
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return jsonFieldName(sf)
+}
+
+func (b *binder) bindStruct(v reflect.Value, prefix, path string) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField() && !b.tooComplex; i++ {
+		sf := typ.Field(i)
+		fv := v.Field(i)
+
+		name, ok := b.fieldName(sf)
+		if !ok {
+			continue
+		}
+		if sf.Anonymous && name == sf.Name && fv.Kind() == reflect.Struct {
+			b.bindStruct(fv, prefix, path)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		key := prefix + name
+		fieldPath := path + "/" + escapeJSONPointer(name)
+
+		if pathName := sf.Tag.Get("path"); pathName != "" && b.pathValue != nil {
+			if value := b.pathValue(pathName); value != "" {
+				b.setField(fv, sf, []string{value}, fieldPath)
+			}
+			continue
+		}
+
+		switch {
+		case sf.Type == uploadedFileType || sf.Type == uploadedFilesType:
+			b.bindFiles(fv, key, fieldPath)
+		case isNestedStruct(sf.Type):
+			if b.hasPrefix(key + ".") {
+				b.bindStruct(allocate(fv), key+".", fieldPath)
+			}
+		case sf.Type.Kind() == reflect.Slice && isNestedStruct(sf.Type.Elem()):
+			b.bindStructSlice(fv, key, fieldPath)
+		default:
+			values, found := b.lookup(key, sf.Type.Kind() == reflect.Slice)
+			if !found {
+				def, hasDefault := sf.Tag.Lookup("default")
+				if !hasDefault {
+					continue
+				}
+				values = []string{def}
+			}
+			b.setField(fv, sf, values, fieldPath)
+		}
+	}
+}
+
+// bindStructSlice preenche uma lista de structs a partir de chaves indexadas.
+// Os índices devem ser contíguos a partir de 0, de modo que cada elemento
+// alocado corresponda a chaves presentes na requisição.
+func (b *binder) bindStructSlice(fv reflect.Value, key, path string) {
+	if !b.spend() {
+		return
+	}
+	indexes := map[int]bool{}
+	for k := range b.values {
+		rest, ok := strings.CutPrefix(k, key+"[")
+		if !ok {
+			continue
+		}
+		idx, _, ok := strings.Cut(rest, "]")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(idx)
+		if err != nil || n < 0 || n > maxBindIndex {
+			b.errs = append(b.errs, &FieldError{Field: path, Rule: "index", Message: fmt.Sprintf("invalid index %q", idx)})
+			return
+		}
+		indexes[n] = true
+	}
+	if len(indexes) == 0 {
+		return
+	}
+
+	sorted := make([]int, 0, len(indexes))
+	for n := range indexes {
+		sorted = append(sorted, n)
+	}
+	sort.Ints(sorted)
+	if last := sorted[len(sorted)-1]; last >= len(sorted) {
+		b.errs = append(b.errs, &FieldError{Field: path, Rule: "index", Message: fmt.Sprintf("indexes must be contiguous starting at 0, got %d", last)})
+		return
+	}
+
+	slice := reflect.MakeSlice(fv.Type(), sorted[len(sorted)-1]+1, sorted[len(sorted)-1]+1)
+	for _, n := range sorted {
+		elemKey := fmt.Sprintf("%s[%d].", key, n)
+		b.bindStruct(allocate(slice.Index(n)), elemKey, fmt.Sprintf("%s/%d", path, n))
+	}
+	fv.Set(slice)
+}
+
+var (
+	uploadedFileType  = reflect.TypeFor[*UploadedFile]()
+	uploadedFilesType = reflect.TypeFor[[]*UploadedFile]()
+)
+
+// bindFiles grava os arquivos enviados com a chave informada e preenche o
+// campo com o resultado.
+func (b *binder) bindFiles(fv reflect.Value, key, path string) {
+	headers := b.files[key]
+	if len(headers) == 0 || b.saveFile == nil {
+		return
+	}
+	if fv.Type() == uploadedFileType {
+		headers = headers[:1]
+	}
+
+	var uploaded []*UploadedFile
+	for _, hdr := range headers {
+		file, err := b.saveFile(hdr)
+		if err != nil {
+			b.errs = append(b.errs, &FieldError{Field: path, Rule: "file", Message: err.Error()})
+			return
+		}
+		uploaded = append(uploaded, file)
+	}
+
+	if fv.Type() == uploadedFileType {
+		fv.Set(reflect.ValueOf(uploaded[0]))
+		return
+	}
+	fv.Set(reflect.ValueOf(uploaded))
+}
+
+// maxBindIndex limita os índices aceitos em listas, evitando alocações
+// arbitrariamente grandes a partir de chaves como "itens[99999999].nome".
+const maxBindIndex = 1000
+
+// maxBindWork limita o total de chaves percorridas em um preenchimento, já que
+// cada struct aninhada e cada lista de structs percorrem todas as chaves.
+const maxBindWork = 1 << 20
+
+// spend contabiliza uma varredura das chaves e retorna false quando o limite
+// de maxBindWork foi excedido.
+func (b *binder) spend() bool {
+	b.work += len(b.values) + len(b.files)
+	if b.work > maxBindWork {
+		b.tooComplex = true
+	}
+	return !b.tooComplex
+}
+
+// lookup retorna os valores de uma chave. Para listas, também aceita a
+// notação "chave[]" e, se habilitado, valores separados por vírgula.
+func (b *binder) lookup(key string, isSlice bool) ([]string, bool) {
+	values, found := b.values[key]
+	if isSlice {
+		if more, ok := b.values[key+"[]"]; ok {
+			values, found = append(values, more...), true
+		}
+		if b.splitCommas {
+			var split []string
+			for _, value := range values {
+				split = append(split, strings.Split(value, ",")...)
+			}
+			values = split
+		}
+	}
+	return values, found
+}
+
+func (b *binder) hasPrefix(prefix string) bool {
+	if !b.spend() {
+		return false
+	}
+	for k := range b.values {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	for k := range b.files {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setField converte os valores de texto para o tipo do campo, registrando um
+// erro de campo em caso de falha.
+func (b *binder) setField(fv reflect.Value, sf reflect.StructField, values []string, path string) {
+	if sf.Type.Kind() == reflect.Slice && !isScalarText(sf.Type) {
+		slice := reflect.MakeSlice(sf.Type, 0, len(values))
+		for _, value := range values {
+			elem := reflect.New(sf.Type.Elem()).Elem()
+			if err := setScalar(elem, value, sf.Tag.Get("layout")); err != nil {
+				b.errs = append(b.errs, &FieldError{Field: path, Rule: "type", Message: err.Error()})
+				return
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fv.Set(slice)
+		return
+	}
+
+	if len(values) == 0 {
+		return
+	}
+	if err := setScalar(fv, values[0], sf.Tag.Get("layout")); err != nil {
+		b.errs = append(b.errs, &FieldError{Field: path, Rule: "type", Message: err.Error()})
+	}
+}
+
+// setScalar converte um único valor de texto para o tipo de v.
+func setScalar(v reflect.Value, value, layout string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setScalar(elem.Elem(), value, layout); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		parsed, err := time.Parse(layout, value)
+		if err != nil {
+			return fmt.Errorf("must be a time in the format %s", layout)
+		}
+		v.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+	if v.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration, such as 1h30m")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("is invalid: %v", err)
+			}
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		switch strings.ToLower(value) {
+		case "on", "yes":
+			v.SetBool(true)
+			return nil
+		case "off", "no":
+			v.SetBool(false)
+			return nil
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		v.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		v.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+		v.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		v.SetFloat(parsed)
+	default:
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// isNestedStruct indica se o tipo é uma struct (ou ponteiro para struct) cujos
+// campos devem ser preenchidos individualmente.
+func isNestedStruct(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && !isScalarText(typ)
+}
+
+// isScalarText indica se o tipo é preenchido a partir de um único texto, como
+// time.Time ou tipos que implementam encoding.TextUnmarshaler.
+func isScalarText(typ reflect.Type) bool {
+	if typ == timeType {
+		return true
+	}
+	return reflect.PointerTo(typ).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// allocate retorna o valor a ser preenchido, alocando ponteiros nulos.
+func allocate(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return v.Elem()
+	}
+	return v
+}
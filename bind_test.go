@@ -0,0 +1,278 @@
+package toolkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryParams struct {
+	ID       int           `path:"id"`
+	Status   string        `query:"status" default:"open" validate:"oneof=open closed"`
+	Page     int           `query:"page" default:"1" validate:"min=1"`
+	Verbose  bool          `query:"verbose"`
+	Tags     []string      `query:"tag"`
+	IDs      []int         `query:"ids"`
+	Since    time.Time     `query:"since" layout:"2006-01-02"`
+	Timeout  time.Duration `query:"timeout"`
+	Limit    *int          `query:"limit"`
+	Internal string        `query:"-"`
+}
+
+func TestTools_ReadQuery(t *testing.T) {
+	var testTools Tools
+
+	t.Run("preenche todos os tipos", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/items/7?status=closed&verbose=true&tag=a&tag=b&ids=1,2,3&since=2024-05-01&timeout=1m30s&limit=5&Internal=x", nil)
+		req.SetPathValue("id", "7")
+
+		var params queryParams
+		if err := testTools.ReadQuery(req, &params); err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+
+		if params.ID != 7 || params.Status != "closed" || params.Page != 1 || !params.Verbose {
+			t.Errorf("valores simples incorretos: %+v", params)
+		}
+		if strings.Join(params.Tags, ",") != "a,b" || len(params.IDs) != 3 || params.IDs[2] != 3 {
+			t.Errorf("listas incorretas: tags %v, ids %v", params.Tags, params.IDs)
+		}
+		if !params.Since.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || params.Timeout != 90*time.Second {
+			t.Errorf("tempos incorretos: since %v, timeout %v", params.Since, params.Timeout)
+		}
+		if params.Limit == nil || *params.Limit != 5 {
+			t.Errorf("ponteiro incorreto: %v", params.Limit)
+		}
+		if params.Internal != "" {
+			t.Error("campos ignorados não deveriam ser preenchidos")
+		}
+	})
+
+	t.Run("aplica valores padrão", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/items", nil)
+
+		var params queryParams
+		if err := testTools.ReadQuery(req, &params); err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if params.Status != "open" || params.Page != 1 || params.Limit != nil {
+			t.Errorf("valores padrão incorretos: %+v", params)
+		}
+	})
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedFields []string
+	}{
+		{name: "tipos inválidos", query: "?page=abc&verbose=talvez&since=ontem", expectedFields: []string{"/page", "/verbose", "/since"}},
+		{name: "regras de validação", query: "?status=pending&page=0", expectedFields: []string{"/status", "/page"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/items"+tc.query, nil)
+
+			var params queryParams
+			err := testTools.ReadQuery(req, &params)
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("esperado ValidationErrors, mas obteve %v", err)
+			}
+			var fields []string
+			for _, e := range verrs {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tc.expectedFields, ",") {
+				t.Errorf("campos com erro incorretos: esperado %v, mas obteve %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestTools_ReadQuery_TooComplex(t *testing.T) {
+	type nested struct {
+		Rows []struct {
+			Cell struct {
+				X string `query:"x"`
+				Y string `query:"y"`
+			} `query:"cell"`
+		} `query:"rows"`
+	}
+
+	var testTools Tools
+	var query strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&query, "rows[%d].cell.x=a&rows[%d].cell.y=b&", i, i)
+	}
+	req := httptest.NewRequest("GET", "/?"+query.String(), nil)
+
+	var params nested
+	err := testTools.ReadQuery(req, &params)
+	if !errors.Is(err, ErrMalformedBody) {
+		t.Errorf("esperado ErrMalformedBody ao exceder o limite de trabalho, mas obteve %v", err)
+	}
+}
+
+type formItem struct {
+	Name     string `form:"name" validate:"required"`
+	Quantity int    `form:"quantity" validate:"min=1"`
+}
+
+type formParams struct {
+	Title   string `form:"title" validate:"required"`
+	Address struct {
+		Street string `form:"street"`
+	} `form:"address"`
+	Items       []formItem      `form:"items"`
+	Attachment  *UploadedFile   `form:"attachment"`
+	Attachments []*UploadedFile `form:"attachments"`
+}
+
+func TestTools_ReadForm(t *testing.T) {
+	t.Run("formulário urlencoded", func(t *testing.T) {
+		var testTools Tools
+		body := "title=Pedido&address.street=Rua+A&items[0].name=caneta&items[0].quantity=2&items[1].name=papel&items[1].quantity=5"
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var params formParams
+		if err := testTools.ReadForm(httptest.NewRecorder(), req, &params); err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if params.Title != "Pedido" || params.Address.Street != "Rua A" {
+			t.Errorf("valores simples incorretos: %+v", params)
+		}
+		if len(params.Items) != 2 || params.Items[1].Name != "papel" || params.Items[1].Quantity != 5 {
+			t.Errorf("itens incorretos: %+v", params.Items)
+		}
+	})
+
+	t.Run("formulário multipart com arquivos", func(t *testing.T) {
+		var testTools Tools
+		uploadDir := t.TempDir()
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("title", "Relatório")
+		for _, name := range []string{"attachment", "attachments", "attachments"} {
+			part, _ := writer.CreateFormFile(name, name+".txt")
+			_, _ = io.WriteString(part, "conteúdo de "+name)
+		}
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/reports", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		var params formParams
+		if err := testTools.ReadForm(httptest.NewRecorder(), req, &params, uploadDir); err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if params.Title != "Relatório" {
+			t.Errorf("título incorreto: %q", params.Title)
+		}
+		if params.Attachment == nil || len(params.Attachments) != 2 {
+			t.Fatalf("arquivos incorretos: %+v, %+v", params.Attachment, params.Attachments)
+		}
+		if _, err := os.Stat(filepath.Join(uploadDir, params.Attachment.NewFileName)); err != nil {
+			t.Errorf("o arquivo deveria ter sido gravado: %v", err)
+		}
+	})
+
+	t.Run("arquivos removidos quando a validação falha", func(t *testing.T) {
+		var testTools Tools
+		uploadDir := t.TempDir()
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("attachment", "attachment.txt")
+		_, _ = io.WriteString(part, "conteúdo")
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/reports", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		var params formParams
+		var verrs ValidationErrors
+		if err := testTools.ReadForm(httptest.NewRecorder(), req, &params, uploadDir); !errors.As(err, &verrs) {
+			t.Fatalf("esperado ValidationErrors, mas obteve %v", err)
+		}
+		if entries, _ := os.ReadDir(uploadDir); len(entries) != 0 {
+			t.Errorf("nenhum arquivo deveria permanecer em uploadDir, mas há %d", len(entries))
+		}
+	})
+
+	t.Run("índice esparso recusado", func(t *testing.T) {
+		var testTools Tools
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader("title=a&items[0].name=a&items[999].name=b"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var params formParams
+		err := testTools.ReadForm(httptest.NewRecorder(), req, &params)
+
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) || verrs[0].Field != "/items" || verrs[0].Rule != "index" {
+			t.Fatalf("esperado erro de índice em /items, mas obteve %v", err)
+		}
+		if params.Items != nil {
+			t.Errorf("nenhum item deveria ser alocado, mas há %d", len(params.Items))
+		}
+	})
+
+	t.Run("regras de validação", func(t *testing.T) {
+		var testTools Tools
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader("items[0].quantity=0"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var params formParams
+		err := testTools.ReadForm(httptest.NewRecorder(), req, &params)
+
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("esperado ValidationErrors, mas obteve %v", err)
+		}
+		expected := []string{"/title", "/items/0/name", "/items/0/quantity"}
+		var fields []string
+		for _, e := range verrs {
+			fields = append(fields, e.Field)
+		}
+		if strings.Join(fields, ",") != strings.Join(expected, ",") {
+			t.Errorf("campos com erro incorretos: esperado %v, mas obteve %v", expected, fields)
+		}
+	})
+
+	testCases := []struct {
+		name          string
+		contentType   string
+		body          string
+		maxSize       int
+		expectedError error
+	}{
+		{name: "corpo muito grande", contentType: "application/x-www-form-urlencoded", body: "title=" + strings.Repeat("a", 100), maxSize: 20, expectedError: ErrBodyTooLarge},
+		{name: "formulário malformado", contentType: "application/x-www-form-urlencoded", body: "title=%zz", expectedError: ErrMalformedBody},
+		{name: "tipo não suportado", contentType: "application/json", body: `{"title": "x"}`, expectedError: ErrUnsupportedMediaType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTools := Tools{MaxJSONSize: tc.maxSize}
+			req := httptest.NewRequest("POST", "/orders", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			var params formParams
+			err := testTools.ReadForm(httptest.NewRecorder(), req, &params)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("esperado %v, mas obteve %v", tc.expectedError, err)
+			}
+		})
+	}
+}
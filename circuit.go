@@ -0,0 +1,193 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen é retornado pelas chamadas remotas enquanto o circuito do
+// host de destino está aberto.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState é o estado do circuito de um host.
+type CircuitState int
+
+const (
+	// CircuitClosed permite as chamadas e conta as falhas consecutivas.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejeita as chamadas com ErrCircuitOpen até o fim do Cooldown.
+	CircuitOpen
+	// CircuitHalfOpen permite chamadas de teste: um sucesso fecha o circuito
+	// e uma falha o abre novamente.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreaker interrompe as chamadas remotas para hosts que estão falhando.
+// Cada host tem o seu próprio circuito: após FailureThreshold falhas
+// consecutivas o circuito abre e as chamadas falham imediatamente com
+// ErrCircuitOpen; passado o Cooldown, até HalfOpenMaxCalls chamadas de teste
+// decidem se o circuito volta a fechar.
+type CircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+	HalfOpenMaxCalls int
+
+	// IsFailure define quais chamadas contam como falha. Por padrão, erros de
+	// rede e respostas 5xx. Chamadas canceladas pelo contexto não são contadas.
+	IsFailure func(resp *http.Response, err error) bool
+
+	// OnStateChange é chamado a cada mudança de estado de um host, fora do
+	// lock do CircuitBreaker.
+	OnStateChange func(host string, from, to CircuitState)
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	state      CircuitState
+	failures   int
+	openedAt   time.Time
+	probes     int
+	generation int
+}
+
+// NewCircuitBreaker cria um CircuitBreaker que abre o circuito de um host após
+// failureThreshold falhas consecutivas e o mantém aberto por cooldown.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		HalfOpenMaxCalls: 1,
+	}
+}
+
+// State retorna o estado atual do circuito de host.
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if c, ok := cb.hosts[host]; ok {
+		if c.state == CircuitOpen && time.Since(c.openedAt) >= cb.Cooldown {
+			return CircuitHalfOpen
+		}
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// allow verifica se uma chamada para host pode ser feita. Em caso de sucesso,
+// retorna a função que registra o resultado da chamada.
+func (cb *CircuitBreaker) allow(host string) (func(resp *http.Response, err error), error) {
+	cb.mu.Lock()
+	if cb.hosts == nil {
+		cb.hosts = make(map[string]*circuit)
+	}
+	c, ok := cb.hosts[host]
+	if !ok {
+		c = &circuit{}
+		cb.hosts[host] = c
+	}
+
+	var changed []CircuitState
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= cb.Cooldown {
+		changed = cb.transition(c, CircuitHalfOpen)
+	}
+
+	switch {
+	case c.state == CircuitOpen, c.state == CircuitHalfOpen && c.probes >= max(cb.HalfOpenMaxCalls, 1):
+		cb.mu.Unlock()
+		cb.notify(host, changed)
+		return nil, ErrCircuitOpen
+	case c.state == CircuitHalfOpen:
+		c.probes++
+	}
+	generation := c.generation
+	cb.mu.Unlock()
+	cb.notify(host, changed)
+
+	return func(resp *http.Response, err error) {
+		cb.record(host, c, generation, resp, err)
+	}, nil
+}
+
+func (cb *CircuitBreaker) record(host string, c *circuit, generation int, resp *http.Response, err error) {
+	cb.mu.Lock()
+	// Resultados de chamadas iniciadas antes da última mudança de estado são ignorados.
+	if c.generation != generation {
+		cb.mu.Unlock()
+		return
+	}
+	// Chamadas canceladas pelo chamador não indicam falha do host.
+	if errors.Is(err, context.Canceled) {
+		if c.state == CircuitHalfOpen {
+			c.probes--
+		}
+		cb.mu.Unlock()
+		return
+	}
+
+	failed := cb.isFailure(resp, err)
+
+	var changed []CircuitState
+	switch c.state {
+	case CircuitHalfOpen:
+		if failed {
+			changed = cb.transition(c, CircuitOpen)
+		} else {
+			changed = cb.transition(c, CircuitClosed)
+		}
+	case CircuitClosed:
+		if !failed {
+			c.failures = 0
+			break
+		}
+		c.failures++
+		if c.failures >= max(cb.FailureThreshold, 1) {
+			changed = cb.transition(c, CircuitOpen)
+		}
+	}
+	cb.mu.Unlock()
+	cb.notify(host, changed)
+}
+
+// transition muda o estado de c e retorna os estados anterior e novo para notify.
+func (cb *CircuitBreaker) transition(c *circuit, to CircuitState) []CircuitState {
+	from := c.state
+	c.state = to
+	c.generation++
+	c.failures = 0
+	c.probes = 0
+	if to == CircuitOpen {
+		c.openedAt = time.Now()
+	}
+	return []CircuitState{from, to}
+}
+
+func (cb *CircuitBreaker) notify(host string, changed []CircuitState) {
+	if cb.OnStateChange != nil && len(changed) == 2 {
+		cb.OnStateChange(host, changed[0], changed[1])
+	}
+}
+
+func (cb *CircuitBreaker) isFailure(resp *http.Response, err error) bool {
+	if cb.IsFailure != nil {
+		return cb.IsFailure(resp, err)
+	}
+	return err != nil || resp.StatusCode >= 500
+}
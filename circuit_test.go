@@ -0,0 +1,138 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error": true}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"error": false}`))
+	}))
+	defer server.Close()
+	host := urlHost(server.URL)
+
+	var mu sync.Mutex
+	var changes []string
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond)
+	breaker.OnStateChange = func(h string, from, to CircuitState) {
+		mu.Lock()
+		defer mu.Unlock()
+		if h != host {
+			t.Errorf("host incorreto na notificação: %s", h)
+		}
+		changes = append(changes, from.String()+"->"+to.String())
+	}
+	testTools := Tools{CircuitBreaker: breaker}
+
+	push := func() (int, error) {
+		_, status, err := testTools.PushJSONToRemote(server.URL, nil)
+		return status, err
+	}
+
+	// Duas falhas consecutivas abrem o circuito.
+	for i := 0; i < 2; i++ {
+		if status, err := push(); err != nil || status != http.StatusBadGateway {
+			t.Fatalf("esperado 502, mas obteve %d, %v", status, err)
+		}
+	}
+	if breaker.State(host) != CircuitOpen {
+		t.Fatalf("o circuito deveria estar aberto, mas está %s", breaker.State(host))
+	}
+
+	// Com o circuito aberto, a chamada falha sem chegar ao servidor.
+	if _, err := push(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("esperado ErrCircuitOpen, mas obteve %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("o servidor não deveria receber chamadas com o circuito aberto: %d", calls.Load())
+	}
+
+	// Após o cooldown, uma falha na chamada de teste reabre o circuito.
+	time.Sleep(60 * time.Millisecond)
+	if breaker.State(host) != CircuitHalfOpen {
+		t.Fatalf("o circuito deveria estar meio aberto, mas está %s", breaker.State(host))
+	}
+	_, _ = push()
+	if breaker.State(host) != CircuitOpen {
+		t.Fatalf("o circuito deveria reabrir após falha no teste, mas está %s", breaker.State(host))
+	}
+
+	// Um sucesso na chamada de teste fecha o circuito.
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+	if status, err := push(); err != nil || status != http.StatusOK {
+		t.Fatalf("esperado 200, mas obteve %d, %v", status, err)
+	}
+	if breaker.State(host) != CircuitClosed {
+		t.Fatalf("o circuito deveria estar fechado, mas está %s", breaker.State(host))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(expected) {
+		t.Fatalf("mudanças de estado incorretas: esperado %v, mas obteve %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("mudanças de estado incorretas: esperado %v, mas obteve %v", expected, changes)
+			break
+		}
+	}
+}
+
+func TestCircuitBreaker_HalfOpenLimit(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Millisecond)
+
+	done, err := breaker.allow("api.example.com")
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	done(nil, errors.New("connection refused"))
+	time.Sleep(5 * time.Millisecond)
+
+	probe, err := breaker.allow("api.example.com")
+	if err != nil {
+		t.Fatalf("a primeira chamada de teste deveria ser permitida: %v", err)
+	}
+	if _, err := breaker.allow("api.example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("apenas uma chamada de teste deveria ser permitida, mas obteve %v", err)
+	}
+
+	// Cancelamentos liberam a vaga de teste sem mudar o estado.
+	probe(nil, context.Canceled)
+	if breaker.State("api.example.com") != CircuitHalfOpen {
+		t.Errorf("o cancelamento não deveria mudar o estado: %s", breaker.State("api.example.com"))
+	}
+	if _, err := breaker.allow("api.example.com"); err != nil {
+		t.Errorf("a vaga de teste deveria ter sido liberada: %v", err)
+	}
+
+	// Outros hosts não são afetados.
+	if breaker.State("other.example.com") != CircuitClosed {
+		t.Error("o circuito de outro host deveria estar fechado")
+	}
+}
+
+func urlHost(rawURL string) string {
+	u, _ := url.Parse(rawURL)
+	return u.Host
+}
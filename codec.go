@@ -0,0 +1,288 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Encoder serializa respostas em um formato específico. É usado por
+// WriteResponse conforme o cabeçalho Accept da requisição.
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+}
+
+// Decoder desserializa corpos de requisição em um formato específico. É usado
+// por ReadRequest conforme o cabeçalho Content-Type da requisição.
+type Decoder interface {
+	ContentType() string
+	Decode(r io.Reader, v interface{}) error
+}
+
+// JSONCodec codifica e decodifica application/json.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return "application/json" }
+
+func (JSONCodec) Encode(w io.Writer, v interface{}) error {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func (JSONCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// XMLCodec codifica e decodifica application/xml. Structs seguem as regras de
+// encoding/xml. Mapas e listas no nível superior são envolvidos em um elemento
+// <response>: cada chave do mapa vira um elemento (ou <entry key="..."> quando
+// não é um nome XML válido) e cada item da lista, um elemento <item>. Mapas
+// dentro de structs continuam sujeitos às limitações de encoding/xml e exigem
+// um método MarshalXML, como o de JSONResponse e ProblemDetails.
+type XMLCodec struct{}
+
+func (XMLCodec) ContentType() string { return "application/xml" }
+
+func (XMLCodec) Encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return enc.EncodeElement(xmlValue{v}, xml.StartElement{Name: xml.Name{Local: "response"}})
+	}
+	return enc.Encode(v)
+}
+
+// xmlValue serializa mapas com chaves string e listas, que encoding/xml não
+// suporta, como elementos aninhados. Os demais valores usam encoding/xml.
+type xmlValue struct{ v interface{} }
+
+func (x xmlValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := reflect.ValueOf(x.v)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil():
+		return e.EncodeElement("", start)
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if err := e.EncodeElement(xmlValue{value.Interface()}, xmlKeyElement(key)); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := e.EncodeElement(xmlValue{v.Index(i).Interface()}, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	}
+	return e.EncodeElement(v.Interface(), start)
+}
+
+// xmlKeyElement retorna o elemento de uma chave de mapa: o próprio nome, se
+// for um nome XML válido, ou <entry key="...">.
+func xmlKeyElement(key string) xml.StartElement {
+	valid := key != "" && !strings.HasPrefix(strings.ToLower(key), "xml")
+	for i, r := range key {
+		letter := r == '_' || unicode.IsLetter(r)
+		if !letter && (i == 0 || !(unicode.IsDigit(r) || r == '-' || r == '.')) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func (XMLCodec) Decode(r io.Reader, v interface{}) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// encoders retorna os Encoders configurados ou, por padrão, JSON e XML.
+func (t *Tools) encoders() []Encoder {
+	if len(t.Encoders) > 0 {
+		return t.Encoders
+	}
+	return []Encoder{JSONCodec{}, XMLCodec{}}
+}
+
+// decoders retorna os Decoders configurados ou, por padrão, JSON e XML.
+func (t *Tools) decoders() []Decoder {
+	if len(t.Decoders) > 0 {
+		return t.Decoders
+	}
+	return []Decoder{JSONCodec{}, XMLCodec{}}
+}
+
+// NegotiateEncoder escolhe o Encoder mais adequado para o cabeçalho Accept da
+// requisição, respeitando os q-values. Sem Accept, ou se nenhum Encoder for
+// aceitável, retorna o primeiro Encoder configurado.
+func (t *Tools) NegotiateEncoder(r *http.Request) Encoder {
+	encoders := t.encoders()
+	specs := parseAccept(r.Header.Get("Accept"))
+
+	for _, spec := range specs {
+		if spec.q == 0 {
+			break
+		}
+		for _, enc := range encoders {
+			if mediaRangeMatches(spec.value, enc.ContentType()) && mediaTypeQuality(specs, enc.ContentType()) > 0 {
+				return enc
+			}
+		}
+	}
+	return encoders[0]
+}
+
+// mediaRangeMatches verifica se um intervalo do Accept ("*/*", "text/*" ou um
+// tipo exato) inclui o tipo de conteúdo informado.
+func mediaRangeMatches(mediaRange, contentType string) bool {
+	mediaRange, _, _ = strings.Cut(mediaRange, ";")
+	contentType, _, _ = strings.Cut(strings.ToLower(contentType), ";")
+	mediaRange, contentType = strings.TrimSpace(mediaRange), strings.TrimSpace(contentType)
+
+	if mediaRange == "*/*" || mediaRange == contentType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+	return false
+}
+
+// mediaTypeQuality retorna o q do intervalo mais específico que inclui o tipo
+// de conteúdo, de modo que "application/xml;q=0" exclua XML mesmo com "*/*".
+func mediaTypeQuality(specs []acceptSpec, contentType string) float64 {
+	best, bestSpecificity := 0.0, -1
+	for _, spec := range specs {
+		if !mediaRangeMatches(spec.value, contentType) {
+			continue
+		}
+		specificity := 2
+		switch {
+		case spec.value == "*/*":
+			specificity = 0
+		case strings.HasSuffix(spec.value, "/*"):
+			specificity = 1
+		}
+		if specificity > bestSpecificity {
+			best, bestSpecificity = spec.q, specificity
+		}
+	}
+	return best
+}
+
+// WriteResponse serializa data no formato negociado pelo cabeçalho Accept
+// (JSON e XML por padrão; veja Tools.Encoders) e escreve a resposta com o
+// status e os cabeçalhos informados.
+func (t *Tools) WriteResponse(w http.ResponseWriter, r *http.Request, status int, data interface{}, headers ...http.Header) error {
+	enc := t.NegotiateEncoder(r)
+
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, data); err != nil {
+		return err
+	}
+
+	if len(headers) > 0 {
+		for key, value := range headers[0] {
+			w.Header()[key] = value
+		}
+	}
+	addVary(w.Header(), "Accept")
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadRequest decodifica o corpo da requisição conforme o Content-Type.
+// Corpos JSON (ou sem Content-Type) são lidos por ReadJSON, mantendo as suas
+// mensagens de erro; os demais formatos usam o Decoder correspondente, com o
+// mesmo limite de tamanho de MaxJSONSize.
+func (t *Tools) ReadRequest(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return t.ReadJSON(w, r, data)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return newRequestError(ErrUnsupportedMediaType, "unsupported content type %q", contentType)
+	}
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return t.ReadJSON(w, r, data)
+	}
+
+	if mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml") {
+		mediaType = "application/xml"
+	}
+
+	for _, dec := range t.decoders() {
+		if !mediaRangeMatches(mediaType, dec.ContentType()) {
+			continue
+		}
+
+		maxBytes := t.maxJSONBytes()
+		if err := t.limitRequestBody(w, r, maxBytes); err != nil {
+			return err
+		}
+
+		err := dec.Decode(r.Body, data)
+		var maxBytesError *http.MaxBytesError
+		var reqErr *requestError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &reqErr):
+			return reqErr
+		case errors.As(err, &maxBytesError):
+			return newRequestError(ErrBodyTooLarge, "body must not be larger than %d bytes", maxBytes)
+		case errors.Is(err, io.EOF):
+			return newRequestError(ErrEmptyBody, "body must not be empty")
+		default:
+			return newRequestError(ErrMalformedBody, "body contains badly-formed %s: %v", mediaType, err)
+		}
+	}
+
+	return newRequestError(ErrUnsupportedMediaType, "unsupported content type %q", mediaType)
+}
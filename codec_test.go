@@ -0,0 +1,167 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTools_WriteResponse(t *testing.T) {
+	testTools := Tools{Encoders: []Encoder{JSONCodec{}, XMLCodec{}, MsgPackEncoder{}, CBOREncoder{}}}
+
+	type payload struct {
+		Foo string `json:"foo" xml:"foo"`
+	}
+
+	testCases := []struct {
+		name         string
+		accept       string
+		expectedType string
+	}{
+		{name: "sem Accept", accept: "", expectedType: "application/json"},
+		{name: "curinga", accept: "*/*", expectedType: "application/json"},
+		{name: "xml explícito", accept: "application/xml", expectedType: "application/xml"},
+		{name: "q-values", accept: "application/json;q=0.5, application/xml;q=0.9", expectedType: "application/xml"},
+		{name: "exclusão com q=0", accept: "application/json;q=0, */*;q=0.1", expectedType: "application/xml"},
+		{name: "msgpack", accept: "application/msgpack", expectedType: "application/msgpack"},
+		{name: "cbor", accept: "application/cbor, application/json;q=0.5", expectedType: "application/cbor"},
+		{name: "tipo não suportado usa o padrão", accept: "text/csv", expectedType: "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			if err := testTools.WriteResponse(rr, req, http.StatusOK, payload{Foo: "bar"}); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != tc.expectedType {
+				t.Errorf("Content-Type incorreto: esperado '%s', mas obteve '%s'", tc.expectedType, ct)
+			}
+			if rr.Header().Get("Vary") != "Accept" {
+				t.Errorf("cabeçalho Vary incorreto: obteve '%s'", rr.Header().Get("Vary"))
+			}
+			if tc.expectedType == "application/xml" && !strings.Contains(rr.Body.String(), "<foo>bar</foo>") {
+				t.Errorf("corpo XML incorreto: %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestTools_ReadRequest(t *testing.T) {
+	type payload struct {
+		Foo string `json:"foo" xml:"foo"`
+	}
+
+	testCases := []struct {
+		name          string
+		contentType   string
+		body          string
+		expectedFoo   string
+		expectedError error
+	}{
+		{name: "json", contentType: "application/json; charset=utf-8", body: `{"foo": "bar"}`, expectedFoo: "bar"},
+		{name: "sem Content-Type", contentType: "", body: `{"foo": "bar"}`, expectedFoo: "bar"},
+		{name: "xml", contentType: "application/xml", body: `<payload><foo>bar</foo></payload>`, expectedFoo: "bar"},
+		{name: "text/xml", contentType: "text/xml", body: `<payload><foo>bar</foo></payload>`, expectedFoo: "bar"},
+		{name: "xml com sufixo", contentType: "application/atom+xml", body: `<payload><foo>bar</foo></payload>`, expectedFoo: "bar"},
+		{name: "xml inválido", contentType: "application/xml", body: `<payload><foo>`, expectedError: ErrMalformedBody},
+		{name: "tipo não suportado", contentType: "text/csv", body: `foo\nbar`, expectedError: ErrUnsupportedMediaType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var testTools Tools
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			var data payload
+			err := testTools.ReadRequest(httptest.NewRecorder(), req, &data)
+
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Errorf("erro incorreto: esperado %v, mas obteve %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if data.Foo != tc.expectedFoo {
+				t.Errorf("valor incorreto: esperado '%s', mas obteve '%s'", tc.expectedFoo, data.Foo)
+			}
+		})
+	}
+}
+
+func TestBinaryEncoders(t *testing.T) {
+	type payload struct {
+		A int    `json:"a"`
+		B string `json:"b,omitempty"`
+		C int    `json:"-"`
+	}
+
+	testCases := []struct {
+		name     string
+		encoder  Encoder
+		data     interface{}
+		expected []byte
+	}{
+		{name: "msgpack struct", encoder: MsgPackEncoder{}, data: payload{A: 1}, expected: []byte{0x81, 0xa1, 'a', 0x01}},
+		{name: "msgpack negativo", encoder: MsgPackEncoder{}, data: -100, expected: []byte{0xd0, 0x9c}},
+		{name: "msgpack lista", encoder: MsgPackEncoder{}, data: []interface{}{true, nil, "x"}, expected: []byte{0x93, 0xc3, 0xc0, 0xa1, 'x'}},
+		{name: "cbor struct", encoder: CBOREncoder{}, data: payload{A: 1}, expected: []byte{0xa1, 0x61, 'a', 0x01}},
+		{name: "cbor negativo", encoder: CBOREncoder{}, data: -100, expected: []byte{0x38, 0x63}},
+		{name: "cbor inteiro grande", encoder: CBOREncoder{}, data: uint64(1000), expected: []byte{0x19, 0x03, 0xe8}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.encoder.Encode(&buf, tc.data); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tc.expected) {
+				t.Errorf("bytes incorretos: esperado %x, mas obteve %x", tc.expected, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestXMLCodec_Encode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{name: "mapa", data: map[string]interface{}{"b": 2, "a": "x", "com espaço": true}, expected: `<response><a>x</a><b>2</b><entry key="com espaço">true</entry></response>`},
+		{name: "lista", data: []string{"a", "b"}, expected: `<response><item>a</item><item>b</item></response>`},
+		{name: "lista de mapas", data: []map[string]int{{"n": 1}}, expected: `<response><item><n>1</n></item></response>`},
+		{name: "problem details", data: &ProblemDetails{Title: "Erro", Status: 422, Extensions: map[string]interface{}{"errors": []string{"campo"}, "title": "ignorado"}}, expected: `<problem xmlns="urn:ietf:rfc:7807"><title>Erro</title><status>422</status><errors><item>campo</item></errors></problem>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (XMLCodec{}).Encode(&buf, tc.data); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if got := strings.TrimPrefix(buf.String(), xml.Header); got != tc.expected {
+				t.Errorf("XML incorreto:\nesperado %s\nobteve   %s", tc.expected, got)
+			}
+			if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+				t.Errorf("o XML deveria ser bem formado: %v", err)
+			}
+		})
+	}
+}
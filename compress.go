@@ -0,0 +1,298 @@
+package toolkit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CompressWriter é o writer de compressão criado por um Compressor. Reset
+// permite reaproveitar o writer para outra resposta.
+type CompressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compressor cria um CompressWriter para um Content-Encoding, como "br" ou
+// "zstd", registrado em Tools.Compressors.
+type Compressor func(w io.Writer) CompressWriter
+
+// defaultCompressors são os Content-Encodings suportados sem configuração.
+// "deflate" segue a RFC 9110, que o define como dados zlib.
+var defaultCompressors = map[string]Compressor{
+	"gzip":    func(w io.Writer) CompressWriter { return gzip.NewWriter(w) },
+	"deflate": func(w io.Writer) CompressWriter { return zlib.NewWriter(w) },
+}
+
+// compressionPreference define a escolha entre encodings com o mesmo q.
+var compressionPreference = []string{"zstd", "br", "gzip", "deflate"}
+
+// Compress é um middleware que comprime as respostas conforme o cabeçalho
+// Accept-Encoding do cliente. Usa gzip e deflate por padrão, além dos
+// encodings registrados em Compressors (como "br" ou "zstd").
+//
+// Somente tipos de conteúdo listados em CompressibleTypes são comprimidos, e
+// respostas menores que CompressMinSize (1 KB por padrão) são enviadas sem
+// compressão. Respostas que já definem Content-Encoding, respostas parciais e
+// respostas com Cache-Control: no-transform não são alteradas. Ao chamar
+// Flush, como fazem WriteJSONArray, WriteNDJSON e SSEWriter, a compressão é
+// iniciada sem aguardar o tamanho mínimo.
+func (t *Tools) Compress(next http.Handler) http.Handler {
+	compressors := make(map[string]Compressor, len(defaultCompressors)+len(t.Compressors))
+	for enc, c := range defaultCompressors {
+		compressors[enc] = c
+	}
+	for enc, c := range t.Compressors {
+		compressors[strings.ToLower(enc)] = c
+	}
+
+	pools := make(map[string]*sync.Pool, len(compressors))
+	for enc, c := range compressors {
+		pools[enc] = &sync.Pool{New: func() interface{} { return c(io.Discard) }}
+	}
+
+	minSize := t.CompressMinSize
+	if minSize <= 0 {
+		minSize = 1024
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Sem encoding aceitável, e em HEAD, a resposta não é comprimida, mas
+		// passa pelo writer para receber o mesmo Vary da versão comprimida.
+		encoding := negotiateEncoding(parseAccept(r.Header.Get("Accept-Encoding")), pools)
+		if r.Method == http.MethodHead {
+			encoding = ""
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			tools:          t,
+			encoding:       encoding,
+			pool:           pools[encoding], // nil sem encoding
+			minSize:        minSize,
+		}
+		defer cw.finish()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding escolhe o encoding disponível com o maior q, usando
+// compressionPreference como desempate.
+func negotiateEncoding(specs []acceptSpec, pools map[string]*sync.Pool) string {
+	var candidates []string
+	for enc := range pools {
+		candidates = append(candidates, enc)
+	}
+	slices.SortFunc(candidates, func(a, b string) int {
+		ia, ib := slices.Index(compressionPreference, a), slices.Index(compressionPreference, b)
+		if ia < 0 {
+			ia = len(compressionPreference)
+		}
+		if ib < 0 {
+			ib = len(compressionPreference)
+		}
+		if ia != ib {
+			return ia - ib
+		}
+		return strings.Compare(a, b)
+	})
+
+	best, bestQ := "", 0.0
+	for _, enc := range candidates {
+		if q := encodingQuality(specs, enc); q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressResponseWriter acumula o início da resposta até decidir se ela será
+// comprimida: ao atingir o tamanho mínimo, ao receber Flush ou ao final do
+// handler.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	tools    *Tools
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	status   int
+	buf      []byte
+	decided  bool
+	hijacked bool
+	cw       CompressWriter
+}
+
+func (c *compressResponseWriter) WriteHeader(status int) {
+	if c.decided || c.status != 0 {
+		return
+	}
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		c.ResponseWriter.WriteHeader(status)
+		return
+	}
+	c.status = status
+	if !bodyAllowedForStatus(status) {
+		c.decide(false)
+	}
+}
+
+func (c *compressResponseWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		c.buf = append(c.buf, p...)
+		if len(c.buf) < c.minSize {
+			return len(p), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if c.cw != nil {
+		return c.cw.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// decide define se a resposta será comprimida e envia os cabeçalhos e o
+// conteúdo acumulado. Com large falso, respostas menores que o tamanho mínimo
+// não são comprimidas.
+func (c *compressResponseWriter) decide(large bool) error {
+	c.decided = true
+	h := c.Header()
+	if h.Get("Content-Type") == "" && len(c.buf) > 0 && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Type", http.DetectContentType(c.buf))
+	}
+
+	if h.Get("Content-Encoding") == "" && c.tools.isCompressibleType(h.Get("Content-Type")) {
+		addVary(h, "Accept-Encoding")
+	}
+
+	if c.shouldCompress(large) {
+		h.Set("Content-Encoding", c.encoding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		c.cw = c.pool.Get().(CompressWriter)
+		c.cw.Reset(c.ResponseWriter)
+	}
+
+	c.ResponseWriter.WriteHeader(c.status)
+	if len(c.buf) == 0 {
+		return nil
+	}
+	buf := c.buf
+	c.buf = nil
+	if c.cw != nil {
+		_, err := c.cw.Write(buf)
+		return err
+	}
+	_, err := c.ResponseWriter.Write(buf)
+	return err
+}
+
+func (c *compressResponseWriter) shouldCompress(large bool) bool {
+	h := c.Header()
+	switch {
+	case c.encoding == "":
+		return false
+	case !bodyAllowedForStatus(c.status), c.status == http.StatusPartialContent:
+		return false
+	case h.Get("Content-Encoding") != "", h.Get("Content-Range") != "":
+		return false
+	case strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-transform"):
+		return false
+	case !c.tools.isCompressibleType(h.Get("Content-Type")):
+		return false
+	}
+	return large || len(c.buf) >= c.minSize
+}
+
+// FlushError inicia a compressão, se ainda não decidida, e envia os dados
+// comprimidos até aqui para o cliente.
+func (c *compressResponseWriter) FlushError() error {
+	if c.hijacked {
+		return nil
+	}
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		if err := c.decide(true); err != nil {
+			return err
+		}
+	}
+	if c.cw != nil {
+		if err := c.cw.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *compressResponseWriter) Flush() {
+	_ = c.FlushError()
+}
+
+func (c *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("compress: response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil {
+		c.hijacked = true
+	}
+	return conn, rw, err
+}
+
+func (c *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// finish envia o conteúdo pendente e devolve o writer de compressão ao pool.
+func (c *compressResponseWriter) finish() {
+	if c.hijacked {
+		return
+	}
+	if !c.decided {
+		if c.status == 0 && len(c.buf) == 0 {
+			return
+		}
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		_ = c.decide(false)
+	}
+	if c.cw != nil {
+		_ = c.cw.Close()
+		c.cw.Reset(io.Discard)
+		c.pool.Put(c.cw)
+		c.cw = nil
+	}
+}
+
+// bodyAllowedForStatus indica se o status permite corpo na resposta.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,255 @@
+package toolkit
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gunzipString(t *testing.T, r io.Reader) string {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("resposta gzip inválida: %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("resposta gzip inválida: %v", err)
+	}
+	return string(out)
+}
+
+func TestTools_Compress(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+
+	testCases := []struct {
+		name             string
+		acceptEncoding   string
+		handler          http.HandlerFunc
+		expectedEncoding string
+		expectedVary     bool
+		expectedBody     string
+	}{
+		{
+			name:             "JSON grande",
+			acceptEncoding:   "gzip, deflate",
+			handler:          func(w http.ResponseWriter, r *http.Request) { _ = (&Tools{}).WriteJSON(w, http.StatusOK, large) },
+			expectedEncoding: "gzip",
+			expectedVary:     true,
+			expectedBody:     `"` + large + `"`,
+		},
+		{
+			name:           "resposta menor que o tamanho mínimo",
+			acceptEncoding: "gzip",
+			handler:        func(w http.ResponseWriter, r *http.Request) { _ = (&Tools{}).WriteJSON(w, http.StatusOK, "pequeno") },
+			expectedVary:   true,
+			expectedBody:   `"pequeno"`,
+		},
+		{
+			name:           "cliente sem suporte a compressão",
+			acceptEncoding: "",
+			handler:        func(w http.ResponseWriter, r *http.Request) { _ = (&Tools{}).WriteJSON(w, http.StatusOK, large) },
+			expectedVary:   true,
+			expectedBody:   `"` + large + `"`,
+		},
+		{
+			name:           "gzip recusado pelo cliente",
+			acceptEncoding: "gzip;q=0, identity",
+			handler:        func(w http.ResponseWriter, r *http.Request) { _ = (&Tools{}).WriteJSON(w, http.StatusOK, large) },
+			expectedVary:   true,
+			expectedBody:   `"` + large + `"`,
+		},
+		{
+			name:           "tipo não compressível",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = io.WriteString(w, large)
+			},
+			expectedBody: large,
+		},
+		{
+			name:           "Content-Encoding já definido",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				_, _ = io.WriteString(w, large)
+			},
+			expectedEncoding: "br",
+			expectedBody:     large,
+		},
+		{
+			name:           "no-transform",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-transform")
+				_ = (&Tools{}).WriteJSON(w, http.StatusOK, large)
+			},
+			expectedVary: true,
+			expectedBody: `"` + large + `"`,
+		},
+		{
+			name:           "sem conteúdo",
+			acceptEncoding: "gzip",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) },
+		},
+	}
+
+	var testTools Tools
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rr := httptest.NewRecorder()
+
+			testTools.Compress(tc.handler).ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Content-Encoding"); got != tc.expectedEncoding {
+				t.Fatalf("Content-Encoding incorreto: esperado '%s', mas obteve '%s'", tc.expectedEncoding, got)
+			}
+			if got := rr.Header().Get("Vary") == "Accept-Encoding"; got != tc.expectedVary {
+				t.Errorf("Vary incorreto: obteve '%s'", rr.Header().Get("Vary"))
+			}
+
+			body := rr.Body.String()
+			if tc.expectedEncoding == "gzip" {
+				body = gunzipString(t, rr.Body)
+			}
+			if body != tc.expectedBody {
+				t.Errorf("corpo incorreto: esperado %d bytes, mas obteve %d", len(tc.expectedBody), len(body))
+			}
+		})
+	}
+}
+
+func TestTools_Compress_Head(t *testing.T) {
+	var testTools Tools
+	handler := testTools.Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("HEAD", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Encoding") != "" || rr.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("HEAD deveria ter Vary sem Content-Encoding: %v", rr.Header())
+	}
+}
+
+func TestTools_Compress_DeflateRoundTrip(t *testing.T) {
+	testTools := Tools{CompressMinSize: 10}
+	payload := map[string]string{"message": strings.Repeat("deflate ", 20)}
+	handler := testTools.Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = testTools.WriteJSON(w, http.StatusOK, payload)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "deflate")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("esperado Content-Encoding deflate, mas obteve '%s'", rr.Header().Get("Content-Encoding"))
+	}
+
+	// A resposta deve ser aceita pelo próprio ReadJSON, que segue a RFC 9110.
+	body := httptest.NewRequest("POST", "/", rr.Body)
+	body.Header.Set("Content-Type", "application/json")
+	body.Header.Set("Content-Encoding", "deflate")
+	var got map[string]string
+	if err := testTools.ReadJSON(httptest.NewRecorder(), body, &got); err != nil {
+		t.Fatalf("ReadJSON deveria decodificar a resposta deflate: %v", err)
+	}
+	if got["message"] != payload["message"] {
+		t.Errorf("conteúdo incorreto: %q", got["message"])
+	}
+}
+
+func TestTools_Compress_CustomCompressor(t *testing.T) {
+	testTools := Tools{
+		Compressors: map[string]Compressor{
+			"x-test": func(w io.Writer) CompressWriter { return gzip.NewWriter(w) },
+		},
+		CompressMinSize: 10,
+	}
+	handler := testTools.Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, "conteúdo comprimido")
+	}))
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip;q=0.5, x-test")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Header().Get("Content-Encoding") != "x-test" {
+			t.Fatalf("esperado o encoding preferido pelo cliente, mas obteve '%s'", rr.Header().Get("Content-Encoding"))
+		}
+		if body := gunzipString(t, rr.Body); body != "conteúdo comprimido" {
+			t.Errorf("corpo incorreto na requisição %d: %q", i, body)
+		}
+	}
+}
+
+func TestTools_Compress_Stream(t *testing.T) {
+	testTools := Tools{StreamFlushEvery: 1}
+	handler := testTools.Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = WriteNDJSON(&testTools, w, r, http.StatusOK, func(yield func(int) bool) {
+			for i := 1; i <= 3; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		})
+	}))
+
+	req := httptest.NewRequest("GET", "/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("streams devem ser comprimidos mesmo abaixo do tamanho mínimo, obteve '%s'", rr.Header().Get("Content-Encoding"))
+	}
+	if !rr.Flushed {
+		t.Error("a resposta deveria ter sido enviada com flush")
+	}
+	if body := gunzipString(t, rr.Body); body != "1\n2\n3\n" {
+		t.Errorf("corpo incorreto: %q", body)
+	}
+}
+
+func TestTools_Compress_Hijack(t *testing.T) {
+	var testTools Tools
+	server := httptest.NewServer(testTools.Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("erro inesperado ao assumir a conexão: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+		_ = rw.Flush()
+	})))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "ok" || resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("a conexão assumida não deveria ser alterada: %q %v", body, resp.Header)
+	}
+}
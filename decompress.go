@@ -0,0 +1,99 @@
+package toolkit
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Decompressor cria um leitor que descomprime r conforme um Content-Encoding.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// defaultDecompressors são os Content-Encodings aceitos sem configuração.
+// "deflate" segue a RFC 9110, que o define como dados zlib.
+var defaultDecompressors = map[string]Decompressor{
+	"gzip":    func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	"x-gzip":  func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	"deflate": func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) },
+}
+
+// decompressor retorna o Decompressor para encoding, priorizando
+// BodyDecompressors sobre os padrões.
+func (t *Tools) decompressor(encoding string) (Decompressor, bool) {
+	if d, ok := t.BodyDecompressors[encoding]; ok {
+		return d, true
+	}
+	d, ok := defaultDecompressors[encoding]
+	return d, ok
+}
+
+// limitRequestBody descomprime o corpo conforme o cabeçalho Content-Encoding e
+// limita o resultado a maxBytes. O limite vale para o tamanho descomprimido,
+// protegendo contra bombas de descompressão. Encodings desconhecidos retornam
+// ErrUnsupportedMediaType.
+func (t *Tools) limitRequestBody(w http.ResponseWriter, r *http.Request, maxBytes int) error {
+	var encodings []string
+	for _, value := range r.Header.Values("Content-Encoding") {
+		for _, enc := range strings.Split(value, ",") {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			if enc != "" && enc != "identity" {
+				encodings = append(encodings, enc)
+			}
+		}
+	}
+
+	body := r.Body
+	// Os encodings são listados na ordem em que foram aplicados.
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decompress, ok := t.decompressor(encodings[i])
+		if !ok {
+			return newRequestError(ErrUnsupportedMediaType, "unsupported content encoding %q", encodings[i])
+		}
+		dr, err := decompress(body)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return newRequestError(ErrEmptyBody, "body must not be empty")
+			}
+			return newRequestError(ErrMalformedBody, "body contains badly-formed %s data: %v", encodings[i], err)
+		}
+		body = &decompressedBody{encoding: encodings[i], r: dr, closers: []io.Closer{dr, body}}
+	}
+
+	if len(encodings) > 0 {
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+	}
+	r.Body = http.MaxBytesReader(w, body, int64(maxBytes))
+	return nil
+}
+
+// decompressedBody converte os erros de descompressão em ErrMalformedBody e
+// fecha o leitor descomprimido junto com o corpo original.
+type decompressedBody struct {
+	encoding string
+	r        io.Reader
+	closers  []io.Closer
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		var reqErr *requestError
+		if !errors.As(err, &reqErr) {
+			err = newRequestError(ErrMalformedBody, "body contains badly-formed %s data: %v", b.encoding, err)
+		}
+	}
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	var errs []error
+	for _, c := range b.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,121 @@
+package toolkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func zlibBytes(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestTools_ReadJSON_ContentEncoding(t *testing.T) {
+	const payload = `{"foo": "bar"}`
+
+	testCases := []struct {
+		name          string
+		encoding      string
+		body          []byte
+		maxSize       int
+		expectedError error
+		errorMessage  string
+	}{
+		{name: "sem compressão", encoding: "", body: []byte(payload)},
+		{name: "identity", encoding: "identity", body: []byte(payload)},
+		{name: "gzip", encoding: "gzip", body: gzipBytes(t, payload)},
+		{name: "gzip em maiúsculas", encoding: "GZIP", body: gzipBytes(t, payload)},
+		{name: "deflate", encoding: "deflate", body: zlibBytes(t, payload)},
+		{name: "encodings encadeados", encoding: "deflate, gzip", body: gzipBytes(t, string(zlibBytes(t, payload)))},
+		{name: "limite aplicado ao tamanho descomprimido", encoding: "gzip", body: gzipBytes(t, `{"foo": "`+strings.Repeat("a", 1000)+`"}`), maxSize: 100, expectedError: ErrBodyTooLarge, errorMessage: "body must not be larger than 100 bytes"},
+		{name: "encoding desconhecido", encoding: "br", body: []byte(payload), expectedError: ErrUnsupportedMediaType, errorMessage: `unsupported content encoding "br"`},
+		{name: "gzip inválido", encoding: "gzip", body: []byte(payload), expectedError: ErrMalformedBody},
+		{name: "gzip truncado", encoding: "gzip", body: gzipBytes(t, payload)[:20], expectedError: ErrMalformedBody},
+		{name: "gzip vazio", encoding: "gzip", body: nil, expectedError: ErrEmptyBody},
+	}
+
+	for engineName, engine := range jsonEngines(t) {
+		for _, tc := range testCases {
+			t.Run(engineName+"/"+tc.name, func(t *testing.T) {
+				testTools := Tools{JSONEngine: engine, MaxJSONSize: tc.maxSize}
+				req := httptest.NewRequest("POST", "/ingest", bytes.NewReader(tc.body))
+				if tc.encoding != "" {
+					req.Header.Set("Content-Encoding", tc.encoding)
+				}
+
+				var data struct {
+					Foo string `json:"foo"`
+				}
+				err := testTools.ReadJSON(httptest.NewRecorder(), req, &data)
+
+				if tc.expectedError == nil {
+					if err != nil {
+						t.Fatalf("erro inesperado recebido: %v", err)
+					}
+					if data.Foo != "bar" {
+						t.Errorf("valor incorreto: %q", data.Foo)
+					}
+					return
+				}
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("esperado %v, mas obteve %v", tc.expectedError, err)
+				}
+				if tc.errorMessage != "" && err.Error() != tc.errorMessage {
+					t.Errorf("mensagem incorreta: esperado '%s', mas obteve '%s'", tc.errorMessage, err.Error())
+				}
+			})
+		}
+	}
+}
+
+func TestTools_BodyDecompressors(t *testing.T) {
+	// Um decompressor simples que inverte o corpo, no lugar de um decoder zstd.
+	reverse := func(r io.Reader) (io.ReadCloser, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	testTools := Tools{BodyDecompressors: map[string]Decompressor{"zstd": reverse}}
+
+	req := httptest.NewRequest("POST", "/ingest", strings.NewReader(`}"rab" :"oof"{`))
+	req.Header.Set("Content-Encoding", "zstd")
+
+	var data struct {
+		Foo string `json:"foo"`
+	}
+	if err := testTools.ReadRequest(httptest.NewRecorder(), req, &data); err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	if data.Foo != "bar" {
+		t.Errorf("valor incorreto: %q", data.Foo)
+	}
+	if req.Header.Get("Content-Encoding") != "" {
+		t.Error("o cabeçalho Content-Encoding deveria ser removido após a descompressão")
+	}
+}
@@ -0,0 +1,169 @@
+package toolkit
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// precompressedEncodings lista as extensões de arquivos pré-comprimidos
+// procurados ao lado do arquivo original, em ordem de preferência.
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{encoding: "br", extension: ".br"},
+	{encoding: "gzip", extension: ".gz"},
+}
+
+// serveCompressedFile tenta servir uma versão comprimida do arquivo. Primeiro
+// procura irmãos pré-comprimidos (.br, .gz) aceitos pelo cliente; se não houver,
+// e Tools.CompressDownloads estiver habilitado, comprime com gzip em tempo real
+// os tipos compressíveis. Retorna false quando o arquivo deve ser servido sem
+// compressão.
+func (t *Tools) serveCompressedFile(w http.ResponseWriter, r *http.Request, filePath string) bool {
+	contentType := detectFileContentType(filePath)
+	specs := parseAccept(r.Header.Get("Accept-Encoding"))
+
+	var (
+		bestEncoding string
+		bestPath     string
+		bestQ        float64
+		hasSibling   bool
+	)
+	for _, pc := range precompressedEncodings {
+		siblingPath := filePath + pc.extension
+		info, err := os.Stat(siblingPath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		hasSibling = true
+
+		if q := encodingQuality(specs, pc.encoding); q > bestQ {
+			bestEncoding, bestPath, bestQ = pc.encoding, siblingPath, q
+		}
+	}
+
+	onTheFly := t.CompressDownloads && t.isCompressibleType(contentType)
+	if hasSibling || onTheFly {
+		addVary(w.Header(), "Accept-Encoding")
+	}
+
+	if bestEncoding != "" {
+		f, err := os.Open(bestPath)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return false
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Encoding", bestEncoding)
+		http.ServeContent(w, r, filePath, info.ModTime(), f)
+		return true
+	}
+
+	if !onTheFly || encodingQuality(specs, "gzip") == 0 {
+		return false
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	// Mesmas verificações condicionais de http.ServeContent, para que a
+	// compressão não impeça o cache no cliente.
+	if notModified(w, r, info.ModTime()) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.Header().Set("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	// O tamanho final é desconhecido, então Range e Content-Length não se aplicam.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Set("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+	w.Header().Del("Content-Length")
+	w.Header().Del("Accept-Ranges")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return true
+	}
+
+	gz := gzip.NewWriter(w)
+	_, _ = io.Copy(gz, f)
+	_ = gz.Close()
+
+	return true
+}
+
+// notModified indica se a requisição condicional pode ser respondida com 304.
+// If-None-Match é comparado ao ETag já definido na resposta e, quando
+// presente, prevalece sobre If-Modified-Since, como em http.ServeContent.
+func notModified(w http.ResponseWriter, r *http.Request, modtime time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag := strings.TrimPrefix(w.Header().Get("Etag"), "W/")
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modtime.IsZero() || modtime.Equal(time.Unix(0, 0)) {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// Last-Modified tem precisão de segundos.
+	return !modtime.Truncate(time.Second).After(t)
+}
+
+// detectFileContentType determina o tipo de conteúdo do arquivo pela extensão
+// e, se ela for desconhecida, pelos primeiros bytes do conteúdo.
+func detectFileContentType(filePath string) string {
+	if ctype := mime.TypeByExtension(filepath.Ext(filePath)); ctype != "" {
+		return ctype
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(f, buf)
+	return http.DetectContentType(buf[:n])
+}
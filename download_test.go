@@ -0,0 +1,158 @@
+package toolkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTools_DownloadStaticFile_Compressed(t *testing.T) {
+	tempDir := t.TempDir()
+	content := []byte(`{"items": ["` + strings.Repeat("a", 2048) + `"]}`)
+	if err := os.WriteFile(filepath.Join(tempDir, "export.json"), content, 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "export.json.br"), []byte("conteudo-brotli"), 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+	var gzContent bytes.Buffer
+	gz := gzip.NewWriter(&gzContent)
+	_, _ = gz.Write(content)
+	_ = gz.Close()
+	if err := os.WriteFile(filepath.Join(tempDir, "export.json.gz"), gzContent.Bytes(), 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "bundle.js"), content, 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+
+	testCases := []struct {
+		name             string
+		fileName         string
+		acceptEncoding   string
+		compressOnTheFly bool
+		expectedEncoding string
+		expectedVary     bool
+	}{
+		{name: "prefere brotli pré-comprimido", fileName: "export.json", acceptEncoding: "gzip, br", expectedEncoding: "br", expectedVary: true},
+		{name: "respeita q-values", fileName: "export.json", acceptEncoding: "br;q=0.5, gzip", expectedEncoding: "gzip", expectedVary: true},
+		{name: "cliente sem compressão", fileName: "export.json", acceptEncoding: "", expectedEncoding: "", expectedVary: true},
+		{name: "gzip em tempo real", fileName: "bundle.js", acceptEncoding: "gzip", compressOnTheFly: true, expectedEncoding: "gzip", expectedVary: true},
+		{name: "gzip em tempo real desabilitado", fileName: "bundle.js", acceptEncoding: "gzip", expectedEncoding: "", expectedVary: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTools := Tools{CompressDownloads: tc.compressOnTheFly}
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/download", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+
+			testTools.DownloadStaticFile(rr, req, tempDir, tc.fileName, tc.fileName)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status incorreto: esperado %d, mas obteve %d", http.StatusOK, rr.Code)
+			}
+			if got := rr.Header().Get("Content-Encoding"); got != tc.expectedEncoding {
+				t.Errorf("Content-Encoding incorreto: esperado '%s', mas obteve '%s'", tc.expectedEncoding, got)
+			}
+			if got := rr.Header().Get("Vary") == "Accept-Encoding"; got != tc.expectedVary {
+				t.Errorf("cabeçalho Vary incorreto: obteve '%s'", rr.Header().Get("Vary"))
+			}
+			if strings.HasPrefix(rr.Header().Get("Content-Type"), "application/octet-stream") {
+				t.Errorf("Content-Type deveria ser o do arquivo original, obteve '%s'", rr.Header().Get("Content-Type"))
+			}
+
+			body := rr.Body.Bytes()
+			if tc.expectedEncoding == "gzip" {
+				zr, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("corpo gzip inválido: %v", err)
+				}
+				body, _ = io.ReadAll(zr)
+			}
+			if tc.expectedEncoding != "br" && !bytes.Equal(body, content) {
+				t.Error("o corpo da resposta não corresponde ao conteúdo do arquivo")
+			}
+		})
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	specs := parseAccept("gzip;q=0.5, br, identity;q=0, *;q=0.1, bad;q=2")
+
+	expected := []string{"br", "gzip", "*", "identity"}
+	if len(specs) != len(expected) {
+		t.Fatalf("quantidade de itens incorreta: esperado %d, mas obteve %d", len(expected), len(specs))
+	}
+	for i, value := range expected {
+		if specs[i].value != value {
+			t.Errorf("item %d incorreto: esperado '%s', mas obteve '%s'", i, value, specs[i].value)
+		}
+	}
+
+	if q := encodingQuality(specs, "zstd"); q != 0.1 {
+		t.Errorf("q do curinga incorreto: esperado 0.1, mas obteve %v", q)
+	}
+	if q := encodingQuality(specs, "identity"); q != 0 {
+		t.Errorf("q de identity incorreto: esperado 0, mas obteve %v", q)
+	}
+}
+
+func TestTools_DownloadStaticFile_CompressedConditional(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "bundle.js")
+	if err := os.WriteFile(filePath, []byte(strings.Repeat("a", 2048)), 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+	modtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filePath, modtime, modtime); err != nil {
+		t.Fatalf("Falha ao alterar a data do arquivo: %v", err)
+	}
+
+	testCases := []struct {
+		name           string
+		header         string
+		value          string
+		etag           string
+		expectedStatus int
+	}{
+		{name: "If-Modified-Since igual", header: "If-Modified-Since", value: modtime.Format(http.TimeFormat), expectedStatus: http.StatusNotModified},
+		{name: "If-Modified-Since anterior", header: "If-Modified-Since", value: modtime.Add(-time.Hour).Format(http.TimeFormat), expectedStatus: http.StatusOK},
+		{name: "If-None-Match igual", header: "If-None-Match", value: `"v1"`, etag: `"v1"`, expectedStatus: http.StatusNotModified},
+		{name: "If-None-Match diferente", header: "If-None-Match", value: `"v0"`, etag: `"v1"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTools := Tools{CompressDownloads: true}
+
+			rr := httptest.NewRecorder()
+			if tc.etag != "" {
+				rr.Header().Set("ETag", tc.etag)
+			}
+			req := httptest.NewRequest("GET", "/download", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			req.Header.Set(tc.header, tc.value)
+
+			testTools.DownloadStaticFile(rr, req, tempDir, "bundle.js", "bundle.js")
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("status incorreto: esperado %d, mas obteve %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus == http.StatusNotModified && rr.Body.Len() != 0 {
+				t.Error("a resposta 304 não deveria ter corpo")
+			}
+		})
+	}
+}
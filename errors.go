@@ -0,0 +1,45 @@
+package toolkit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Erros sentinela retornados (encapsulados) pelas funções de leitura do toolkit.
+// Use errors.Is para identificá-los; a mensagem amigável é preservada no erro retornado.
+var (
+	ErrBadlyFormedJSON    = errors.New("badly-formed JSON")
+	ErrIncorrectJSONType  = errors.New("incorrect JSON type")
+	ErrEmptyBody          = errors.New("empty body")
+	ErrUnknownField       = errors.New("unknown field")
+	ErrBodyTooLarge       = errors.New("body too large")
+	ErrMultipleJSONValues = errors.New("multiple JSON values")
+	ErrMalformedBody      = errors.New("malformed body")
+	ErrDuplicateJSONKey   = errors.New("duplicate JSON key")
+	ErrJSONTooDeep        = errors.New("JSON nested too deep")
+
+	// ErrUnsupportedMediaType indica que nenhum Decoder aceita o Content-Type da requisição.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// ErrInvalidSignature indica que a assinatura de um webhook está ausente,
+	// é inválida ou tem um timestamp fora da janela permitida.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// requestError associa uma mensagem amigável a um dos erros sentinela do toolkit.
+type requestError struct {
+	kind error
+	msg  string
+}
+
+func newRequestError(kind error, format string, args ...interface{}) error {
+	return &requestError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func (e *requestError) Unwrap() error {
+	return e.kind
+}
@@ -0,0 +1,200 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// needsJSONCheck indica se o corpo precisa ser verificado antes da
+// decodificação, por exigir regras que encoding/json não aplica.
+func (t *Tools) needsJSONCheck() bool {
+	return t.DisallowDuplicateKeys || t.CaseSensitiveFields || t.MaxJSONDepth > 0
+}
+
+// checkJSON percorre os tokens de body verificando chaves duplicadas, a
+// profundidade máxima e, com CaseSensitiveFields, chaves que correspondem a um
+// campo de target apenas ignorando maiúsculas e minúsculas. Erros de sintaxe
+// são ignorados aqui e reportados pela decodificação.
+func (t *Tools) checkJSON(body []byte, target interface{}) error {
+	return t.checkJSONAt(body, target, 1)
+}
+
+// checkJSONAt é checkJSON para um valor que está no nível depth do corpo, como
+// os elementos de um array lido por ReadJSONStream.
+func (t *Tools) checkJSONAt(body []byte, target interface{}, depth int) error {
+	c := &jsonChecker{
+		dec:           json.NewDecoder(bytes.NewReader(body)),
+		duplicates:    t.DisallowDuplicateKeys,
+		caseSensitive: t.CaseSensitiveFields,
+		maxDepth:      t.MaxJSONDepth,
+	}
+	c.dec.UseNumber()
+
+	err := c.value(reflect.TypeOf(target), "", depth)
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return err
+	}
+	return nil
+}
+
+type jsonChecker struct {
+	dec           *json.Decoder
+	duplicates    bool
+	caseSensitive bool
+	maxDepth      int
+}
+
+// value verifica o próximo valor do stream. typ é o tipo de destino, ou nil
+// quando desconhecido; path é o caminho usado nas mensagens de erro.
+func (c *jsonChecker) value(typ reflect.Type, path string, depth int) error {
+	tok, err := c.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	if c.maxDepth > 0 && depth > c.maxDepth {
+		return newRequestError(ErrJSONTooDeep, "body must not be nested more than %d levels", c.maxDepth)
+	}
+	typ = checkTarget(typ)
+
+	if delim == '[' {
+		var elem reflect.Type
+		if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+			elem = typ.Elem()
+		}
+		for c.dec.More() {
+			if err := c.value(elem, path, depth+1); err != nil {
+				return err
+			}
+		}
+		_, err := c.dec.Token()
+		return err
+	}
+
+	seen := map[string]bool{}
+	for c.dec.More() {
+		tok, err := c.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		// Em structs, chaves que resolvem para o mesmo campo (como "name" e
+		// "NAME", sem CaseSensitiveFields) são duplicatas.
+		seenKey := key
+		var field reflect.Type
+		if typ != nil {
+			switch typ.Kind() {
+			case reflect.Map:
+				field = typ.Elem()
+			case reflect.Struct:
+				f, name, exact := lookupJSONField(typ, key)
+				if f != nil && !exact && c.caseSensitive {
+					return newRequestError(ErrUnknownField, "body contains unknown field %q", joinJSONPath(path, key))
+				}
+				if f != nil {
+					seenKey = name
+				}
+				field = f
+			}
+		}
+
+		if c.duplicates {
+			if seen[seenKey] {
+				return newRequestError(ErrDuplicateJSONKey, "body contains duplicate key %q", joinJSONPath(path, key))
+			}
+			seen[seenKey] = true
+		}
+
+		if err := c.value(field, joinJSONPath(path, key), depth+1); err != nil {
+			return err
+		}
+	}
+	_, err = c.dec.Token()
+	return err
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// checkTarget remove ponteiros de typ e retorna nil para tipos cujo conteúdo
+// não pode ser inspecionado, como interfaces e tipos com UnmarshalJSON próprio.
+func checkTarget(typ reflect.Type) reflect.Type {
+	for typ != nil {
+		ptr := reflect.PointerTo(typ)
+		if ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+			return nil
+		}
+		if typ.Kind() != reflect.Pointer {
+			return typ
+		}
+		typ = typ.Elem()
+	}
+	return nil
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonFieldCache guarda, por tipo de struct, os campos indexados pelo nome JSON.
+var jsonFieldCache sync.Map
+
+// lookupJSONField retorna o tipo e o nome JSON do campo de typ que recebe key
+// e se o nome corresponde exatamente, seguindo as regras de encoding/json.
+func lookupJSONField(typ reflect.Type, key string) (reflect.Type, string, bool) {
+	cached, ok := jsonFieldCache.Load(typ)
+	if !ok {
+		fields := map[string]reflect.Type{}
+		collectJSONFields(typ, fields)
+		cached, _ = jsonFieldCache.LoadOrStore(typ, fields)
+	}
+	fields := cached.(map[string]reflect.Type)
+
+	if f, ok := fields[key]; ok {
+		return f, key, true
+	}
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return f, name, false
+		}
+	}
+	return nil, "", false
+}
+
+func collectJSONFields(typ reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name, ok := jsonFieldName(sf)
+		if !ok {
+			continue
+		}
+		if sf.Anonymous && name == sf.Name {
+			if ft := checkTarget(sf.Type); ft != nil && ft.Kind() == reflect.Struct {
+				collectJSONFields(ft, fields)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if _, exists := fields[name]; !exists {
+			fields[name] = sf.Type
+		}
+	}
+}
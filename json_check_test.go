@@ -0,0 +1,79 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTools_ReadJSON_Options(t *testing.T) {
+	type payment struct {
+		ID       json.Number `json:"id"`
+		Amount   int         `json:"amount"`
+		Metadata struct {
+			Tags []string `json:"tags"`
+		} `json:"metadata"`
+	}
+
+	testCases := []struct {
+		name          string
+		tools         Tools
+		json          string
+		expectedError error
+		errorMessage  string
+	}{
+		{name: "chaves duplicadas permitidas por padrão", json: `{"amount": 1, "amount": 2}`},
+		{name: "chave duplicada", tools: Tools{DisallowDuplicateKeys: true}, json: `{"amount": 1, "amount": 2}`, expectedError: ErrDuplicateJSONKey, errorMessage: `body contains duplicate key "amount"`},
+		{name: "chave duplicada aninhada", tools: Tools{DisallowDuplicateKeys: true}, json: `{"metadata": {"tags": [], "tags": []}}`, expectedError: ErrDuplicateJSONKey, errorMessage: `body contains duplicate key "metadata.tags"`},
+		{name: "chave duplicada com outra caixa", tools: Tools{DisallowDuplicateKeys: true}, json: `{"amount": 1, "AMOUNT": 2}`, expectedError: ErrDuplicateJSONKey, errorMessage: `body contains duplicate key "AMOUNT"`},
+		{name: "chaves de caixas diferentes com CaseSensitiveFields", tools: Tools{DisallowDuplicateKeys: true, CaseSensitiveFields: true}, json: `{"amount": 1, "AMOUNT": 2}`, expectedError: ErrUnknownField},
+		{name: "chaves iguais em objetos diferentes", tools: Tools{DisallowDuplicateKeys: true, AllowUnknownFields: true}, json: `{"extra": [{"a": 1}, {"a": 2}]}`},
+		{name: "caixa diferente permitida por padrão", json: `{"Amount": 1}`},
+		{name: "caixa diferente", tools: Tools{CaseSensitiveFields: true}, json: `{"Amount": 1}`, expectedError: ErrUnknownField, errorMessage: `body contains unknown field "Amount"`},
+		{name: "caixa diferente aninhada", tools: Tools{CaseSensitiveFields: true}, json: `{"metadata": {"TAGS": []}}`, expectedError: ErrUnknownField, errorMessage: `body contains unknown field "metadata.TAGS"`},
+		{name: "caixa correta", tools: Tools{CaseSensitiveFields: true}, json: `{"amount": 1, "metadata": {"tags": ["a"]}}`},
+		{name: "profundidade dentro do limite", tools: Tools{MaxJSONDepth: 3}, json: `{"metadata": {"tags": ["a"]}}`},
+		{name: "profundidade excedida", tools: Tools{MaxJSONDepth: 2, AllowUnknownFields: true}, json: `{"extra": [[1]]}`, expectedError: ErrJSONTooDeep, errorMessage: "body must not be nested more than 2 levels"},
+		{name: "JSON malformado com verificações", tools: Tools{DisallowDuplicateKeys: true}, json: `{"amount": }`, expectedError: ErrBadlyFormedJSON},
+		{name: "corpo muito grande com verificações", tools: Tools{MaxJSONDepth: 5, MaxJSONSize: 10}, json: `{"amount": 1000000000}`, expectedError: ErrBodyTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/payments", strings.NewReader(tc.json))
+
+			var p payment
+			err := tc.tools.ReadJSON(httptest.NewRecorder(), req, &p)
+
+			if tc.expectedError == nil {
+				if err != nil {
+					t.Fatalf("erro inesperado recebido: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("esperado %v, mas obteve %v", tc.expectedError, err)
+			}
+			if tc.errorMessage != "" && err.Error() != tc.errorMessage {
+				t.Errorf("mensagem incorreta: esperado '%s', mas obteve '%s'", tc.errorMessage, err.Error())
+			}
+		})
+	}
+}
+
+func TestTools_ReadJSON_UseNumber(t *testing.T) {
+	testTools := Tools{UseNumber: true}
+	req := httptest.NewRequest("POST", "/payments", strings.NewReader(`{"id": 9007199254740993}`))
+
+	var data map[string]interface{}
+	if err := testTools.ReadJSON(httptest.NewRecorder(), req, &data); err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+
+	id, ok := data["id"].(json.Number)
+	if !ok || id.String() != "9007199254740993" {
+		t.Errorf("o número deveria manter a precisão, mas obteve %#v", data["id"])
+	}
+}
@@ -0,0 +1,204 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultMaxErrorBodySize limita a leitura do corpo de respostas de erro quando
+// JSONClient.MaxErrorBodySize não é informado.
+const defaultMaxErrorBodySize = 64 * 1024
+
+// JSONClient faz chamadas JSON para um serviço remoto usando as configurações
+// de Tools (JSONEngine, RemoteTimeout, RetryPolicy, RateLimiter e
+// CircuitBreaker). As chamadas são feitas pelas funções DoJSON, GetJSON,
+// PostJSON, PutJSON, PatchJSON e DeleteJSON, que decodificam a resposta no
+// tipo informado.
+//
+//	client := tools.NewJSONClient("https://api.example.com/v1")
+//	resp, err := toolkit.GetJSON[Order](ctx, client, "/orders/42", toolkit.WithQuery("expand", "items"))
+type JSONClient struct {
+	// BaseURL é o prefixo dos caminhos relativos passados nas chamadas.
+	BaseURL string
+
+	// HTTPClient é o cliente usado nas chamadas. Se nil, é usado um cliente
+	// com o timeout RemoteTimeout.
+	HTTPClient *http.Client
+
+	// Header é enviado em todas as chamadas; os cabeçalhos de WithHeader
+	// prevalecem.
+	Header http.Header
+
+	// MaxErrorBodySize limita a leitura do corpo de respostas fora da faixa
+	// 2xx (64 KB por padrão).
+	MaxErrorBodySize int64
+
+	// Authenticator adiciona as credenciais das chamadas. Se nil, é usado
+	// RemoteAuthenticator.
+	Authenticator Authenticator
+
+	tools *Tools
+}
+
+// NewJSONClient cria um JSONClient para baseURL. Se client for informado, ele
+// é usado no lugar do cliente padrão.
+func (t *Tools) NewJSONClient(baseURL string, client ...*http.Client) *JSONClient {
+	c := &JSONClient{BaseURL: baseURL, Header: make(http.Header), tools: t}
+	if len(client) > 0 {
+		c.HTTPClient = client[0]
+	}
+	return c
+}
+
+// RequestOption altera uma requisição feita por um JSONClient.
+type RequestOption func(r *http.Request)
+
+// WithHeader define um cabeçalho da requisição.
+func WithHeader(key, value string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// WithQuery acrescenta um parâmetro à query string da requisição.
+func WithQuery(key, value string) RequestOption {
+	return func(r *http.Request) {
+		query := r.URL.Query()
+		query.Add(key, value)
+		r.URL.RawQuery = query.Encode()
+	}
+}
+
+// Response é o resultado de uma chamada de um JSONClient.
+type Response[T any] struct {
+	// Data é o corpo de uma resposta 2xx decodificado.
+	Data T
+
+	// StatusCode é o status HTTP da resposta.
+	StatusCode int
+
+	// Raw é a resposta original, com cabeçalhos e status. O corpo já foi
+	// lido e fechado.
+	Raw *http.Response
+
+	// ErrorBody é o corpo de uma resposta fora da faixa 2xx, limitado a
+	// MaxErrorBodySize bytes.
+	ErrorBody []byte
+}
+
+// DoJSON envia uma requisição com o método informado e body (se não for nil)
+// codificado em JSON. Respostas 2xx são decodificadas em Response.Data;
+// respostas 204 ou com corpo vazio mantêm o valor zero de T. O corpo é
+// limitado a MaxRemoteResponseSize bytes. Para as demais,
+// o corpo é guardado em Response.ErrorBody e, com UseRemoteErrors habilitado,
+// também é retornado um *RemoteError.
+func DoJSON[T any](ctx context.Context, c *JSONClient, method, path string, body interface{}, opts ...RequestOption) (*Response[T], error) {
+	t := c.tools
+	if t == nil {
+		t = &Tools{}
+	}
+
+	var reader io.Reader
+	if body != nil {
+		out, err := t.jsonEngine().Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(out)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.resolve(path), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range c.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = t.remoteClient()
+	}
+	auth := c.Authenticator
+	if auth == nil {
+		auth = t.RemoteAuthenticator
+	}
+	resp, err := t.doRemote(client, req, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	res := &Response[T]{StatusCode: resp.StatusCode, Raw: resp}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, truncated, err := readErrorBody(resp, c.MaxErrorBodySize)
+		res.ErrorBody = body
+		if err != nil {
+			return res, err
+		}
+		if t.UseRemoteErrors {
+			return res, t.newRemoteError(resp, body, truncated)
+		}
+		return res, nil
+	}
+
+	if resp.StatusCode == http.StatusNoContent || method == http.MethodHead {
+		return res, nil
+	}
+	err = t.decodeRemoteResponse(resp, &res.Data)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetJSON envia uma requisição GET e decodifica a resposta em T.
+func GetJSON[T any](ctx context.Context, c *JSONClient, path string, opts ...RequestOption) (*Response[T], error) {
+	return DoJSON[T](ctx, c, http.MethodGet, path, nil, opts...)
+}
+
+// PostJSON envia body em uma requisição POST e decodifica a resposta em T.
+func PostJSON[T any](ctx context.Context, c *JSONClient, path string, body interface{}, opts ...RequestOption) (*Response[T], error) {
+	return DoJSON[T](ctx, c, http.MethodPost, path, body, opts...)
+}
+
+// PutJSON envia body em uma requisição PUT e decodifica a resposta em T.
+func PutJSON[T any](ctx context.Context, c *JSONClient, path string, body interface{}, opts ...RequestOption) (*Response[T], error) {
+	return DoJSON[T](ctx, c, http.MethodPut, path, body, opts...)
+}
+
+// PatchJSON envia body em uma requisição PATCH e decodifica a resposta em T.
+func PatchJSON[T any](ctx context.Context, c *JSONClient, path string, body interface{}, opts ...RequestOption) (*Response[T], error) {
+	return DoJSON[T](ctx, c, http.MethodPatch, path, body, opts...)
+}
+
+// DeleteJSON envia uma requisição DELETE e decodifica a resposta em T.
+func DeleteJSON[T any](ctx context.Context, c *JSONClient, path string, opts ...RequestOption) (*Response[T], error) {
+	return DoJSON[T](ctx, c, http.MethodDelete, path, nil, opts...)
+}
+
+// resolve junta path a BaseURL. URLs absolutas são usadas como estão.
+func (c *JSONClient) resolve(path string) string {
+	if c.BaseURL == "" {
+		return path
+	}
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		return path
+	}
+	if path == "" {
+		return c.BaseURL
+	}
+	return strings.TrimRight(c.BaseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,118 @@
+package toolkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type clientOrder struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestJSONClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/json" || r.Header.Get("X-Api-Key") != "segredo" {
+			t.Errorf("cabeçalhos incorretos: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/orders/42":
+			if r.URL.Query().Get("expand") != "items" {
+				t.Errorf("query incorreta: %s", r.URL.RawQuery)
+			}
+			_, _ = io.WriteString(w, `{"id": 42, "status": "open", "extra": true}`)
+		case r.Method == http.MethodPost, r.Method == http.MethodPut, r.Method == http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "application/json" || string(body) != `{"id":7,"status":"new"}` {
+				t.Errorf("corpo incorreto em %s: %s", r.Method, body)
+			}
+			w.Header().Set("X-Method", r.Method)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": "not found", "detail": "`+strings.Repeat("x", 100)+`"}`)
+		}
+	}))
+	defer server.Close()
+
+	var testTools Tools
+	client := testTools.NewJSONClient(server.URL + "/v1/")
+	client.Header.Set("X-Api-Key", "segredo")
+	client.MaxErrorBodySize = 20
+	ctx := context.Background()
+
+	t.Run("GET com query", func(t *testing.T) {
+		resp, err := GetJSON[clientOrder](ctx, client, "/orders/42", WithQuery("expand", "items"))
+		if err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || resp.Data.ID != 42 || resp.Data.Status != "open" {
+			t.Errorf("resposta incorreta: %+v", resp)
+		}
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+		t.Run(method, func(t *testing.T) {
+			send := map[string]func(context.Context, *JSONClient, string, interface{}, ...RequestOption) (*Response[clientOrder], error){
+				http.MethodPost:  PostJSON[clientOrder],
+				http.MethodPut:   PutJSON[clientOrder],
+				http.MethodPatch: PatchJSON[clientOrder],
+			}[method]
+
+			resp, err := send(ctx, client, "orders", clientOrder{ID: 7, Status: "new"})
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if resp.StatusCode != http.StatusCreated || resp.Data.ID != 7 || resp.Raw.Header.Get("X-Method") != method {
+				t.Errorf("resposta incorreta: %+v", resp)
+			}
+		})
+	}
+
+	t.Run("DELETE sem conteúdo", func(t *testing.T) {
+		resp, err := DeleteJSON[clientOrder](ctx, client, "orders/7")
+		if err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if resp.StatusCode != http.StatusNoContent || resp.Data != (clientOrder{}) {
+			t.Errorf("resposta incorreta: %+v", resp)
+		}
+	})
+
+	t.Run("resposta de erro limitada", func(t *testing.T) {
+		resp, err := GetJSON[clientOrder](ctx, client, "missing", WithHeader("X-Trace", "1"))
+		if err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if resp.StatusCode != http.StatusNotFound || string(resp.ErrorBody) != `{"error": "not found` {
+			t.Errorf("corpo de erro incorreto: %d %q", resp.StatusCode, resp.ErrorBody)
+		}
+	})
+}
+
+func TestJSONClient_resolve(t *testing.T) {
+	testCases := []struct {
+		base, path, expected string
+	}{
+		{base: "https://api.example.com/v1", path: "/orders", expected: "https://api.example.com/v1/orders"},
+		{base: "https://api.example.com/v1/", path: "orders?page=2", expected: "https://api.example.com/v1/orders?page=2"},
+		{base: "https://api.example.com/v1", path: "https://other.example.com/x", expected: "https://other.example.com/x"},
+		{base: "", path: "https://api.example.com/x", expected: "https://api.example.com/x"},
+	}
+
+	for _, tc := range testCases {
+		c := JSONClient{BaseURL: tc.base}
+		if got := c.resolve(tc.path); got != tc.expected {
+			t.Errorf("URL incorreta para %q + %q: esperado %q, mas obteve %q", tc.base, tc.path, tc.expected, got)
+		}
+	}
+}
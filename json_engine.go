@@ -0,0 +1,80 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// JSONEngine é a implementação de JSON usada por ReadJSON, WriteJSON,
+// ErrorJSON e PushJSONToRemote. Por padrão é usado JSONv1 (encoding/json).
+type JSONEngine interface {
+	Marshal(v interface{}) ([]byte, error)
+
+	// Decode lê um único valor JSON de r para v. Deve retornar
+	// ErrMultipleJSONValues se houver outro valor após o primeiro.
+	Decode(r io.Reader, v interface{}, opts JSONDecodeOptions) error
+}
+
+// JSONDecodeOptions são as opções de Tools repassadas ao JSONEngine na leitura.
+type JSONDecodeOptions struct {
+	AllowUnknownFields bool
+	UseNumber          bool
+}
+
+// ErrJSONv2Unavailable é retornado por JSONv2 quando encoding/json/v2 não está
+// disponível na versão do Go usada na compilação.
+var ErrJSONv2Unavailable = errors.New("encoding/json/v2 is not available in this build")
+
+var (
+	// JSONv1 usa encoding/json. É o engine padrão.
+	JSONv1 JSONEngine = jsonV1Engine{}
+
+	// JSONv2 usa encoding/json/v2, disponível a partir do Go 1.27 (com o
+	// experimento jsonv2, habilitado por padrão). Segue a semântica mais
+	// estrita do v2: chaves duplicadas e nomes de campos com caixa diferente
+	// são sempre rejeitados, e slices e mapas nil são escritos como [] e {}.
+	JSONv2 JSONEngine = jsonV2Engine{}
+)
+
+// jsonEngine retorna o JSONEngine configurado ou JSONv1.
+func (t *Tools) jsonEngine() JSONEngine {
+	if t.JSONEngine != nil {
+		return t.JSONEngine
+	}
+	return JSONv1
+}
+
+// jsonDecodeOptions retorna as opções de leitura configuradas em Tools.
+func (t *Tools) jsonDecodeOptions() JSONDecodeOptions {
+	return JSONDecodeOptions{AllowUnknownFields: t.AllowUnknownFields, UseNumber: t.UseNumber}
+}
+
+type jsonV1Engine struct{}
+
+func (jsonV1Engine) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonV1Engine) Decode(r io.Reader, v interface{}, opts JSONDecodeOptions) error {
+	dec := newJSONDecoder(r, opts)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return ErrMultipleJSONValues
+	}
+	return nil
+}
+
+// newJSONDecoder cria um json.Decoder com as opções AllowUnknownFields e UseNumber.
+func newJSONDecoder(r io.Reader, opts JSONDecodeOptions) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if !opts.AllowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if opts.UseNumber {
+		dec.UseNumber()
+	}
+	return dec
+}
@@ -0,0 +1,123 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// jsonEngines retorna os engines disponíveis nesta compilação.
+func jsonEngines(t *testing.T) map[string]JSONEngine {
+	engines := map[string]JSONEngine{"v1": JSONv1}
+	if _, err := JSONv2.Marshal(nil); errors.Is(err, ErrJSONv2Unavailable) {
+		t.Log("encoding/json/v2 indisponível, testando apenas o v1")
+	} else {
+		engines["v2"] = JSONv2
+	}
+	return engines
+}
+
+func TestJSONEngine_ReadJSON(t *testing.T) {
+	type order struct {
+		Name    string `json:"name"`
+		Address struct {
+			Street string `json:"street"`
+		} `json:"address"`
+	}
+
+	testCases := []struct {
+		name          string
+		json          string
+		maxSize       int
+		expectedError error
+		errorMessage  string
+	}{
+		{name: "JSON válido", json: `{"name": "a", "address": {"street": "b"}}`},
+		{name: "corpo vazio", json: ``, expectedError: ErrEmptyBody, errorMessage: "body must not be empty"},
+		{name: "JSON incompleto", json: `{"name": "a"`, expectedError: ErrBadlyFormedJSON},
+		{name: "JSON malformado", json: `{"name" "a"}`, expectedError: ErrBadlyFormedJSON},
+		{name: "tipo incorreto", json: `{"address": {"street": 1}}`, expectedError: ErrIncorrectJSONType, errorMessage: `body contains incorrect JSON type for field "address.street"`},
+		{name: "campo desconhecido", json: `{"nome": "a"}`, expectedError: ErrUnknownField, errorMessage: `body contains unknown field "nome"`},
+		{name: "vários valores", json: `{"name": "a"}{"name": "b"}`, expectedError: ErrMultipleJSONValues},
+		{name: "corpo muito grande", json: `{"name": "uma string muito longa"}`, maxSize: 10, expectedError: ErrBodyTooLarge, errorMessage: "body must not be larger than 10 bytes"},
+	}
+
+	for engineName, engine := range jsonEngines(t) {
+		for _, tc := range testCases {
+			t.Run(engineName+"/"+tc.name, func(t *testing.T) {
+				testTools := Tools{JSONEngine: engine, MaxJSONSize: tc.maxSize}
+				req := httptest.NewRequest("POST", "/orders", strings.NewReader(tc.json))
+
+				var o order
+				err := testTools.ReadJSON(httptest.NewRecorder(), req, &o)
+
+				if tc.expectedError == nil {
+					if err != nil {
+						t.Fatalf("erro inesperado recebido: %v", err)
+					}
+					if o.Name != "a" || o.Address.Street != "b" {
+						t.Errorf("valores incorretos: %+v", o)
+					}
+					return
+				}
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("esperado %v, mas obteve %v", tc.expectedError, err)
+				}
+				if tc.errorMessage != "" && err.Error() != tc.errorMessage {
+					t.Errorf("mensagem incorreta: esperado '%s', mas obteve '%s'", tc.errorMessage, err.Error())
+				}
+			})
+		}
+	}
+}
+
+func TestJSONEngine_UseNumber(t *testing.T) {
+	for engineName, engine := range jsonEngines(t) {
+		t.Run(engineName, func(t *testing.T) {
+			testTools := Tools{JSONEngine: engine, UseNumber: true}
+			req := httptest.NewRequest("POST", "/payments", strings.NewReader(`{"id": 9007199254740993, "tags": ["a"]}`))
+
+			var data map[string]interface{}
+			if err := testTools.ReadJSON(httptest.NewRecorder(), req, &data); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if id, ok := data["id"].(json.Number); !ok || id.String() != "9007199254740993" {
+				t.Errorf("o número deveria manter a precisão, mas obteve %#v", data["id"])
+			}
+		})
+	}
+}
+
+func TestJSONEngine_WriteAndPush(t *testing.T) {
+	for engineName, engine := range jsonEngines(t) {
+		t.Run(engineName, func(t *testing.T) {
+			testTools := Tools{JSONEngine: engine}
+
+			rr := httptest.NewRecorder()
+			if err := testTools.ErrorJSON(rr, errors.New("falhou"), http.StatusConflict); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if rr.Code != http.StatusConflict || !strings.Contains(rr.Body.String(), `"message":"falhou"`) {
+				t.Errorf("resposta incorreta: %d %s", rr.Code, rr.Body.String())
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = testTools.WriteJSON(w, http.StatusOK, JSONResponse{Message: string(body)})
+			}))
+			defer server.Close()
+
+			res, status, err := testTools.PushJSONToRemote(server.URL, map[string]string{"foo": "bar"})
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if status != http.StatusOK || res.Message != `{"foo":"bar"}` {
+				t.Errorf("resposta incorreta: %d %+v", status, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package toolkit
+
+import (
+	"encoding/json"
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type jsonV2Engine struct{}
+
+func (jsonV2Engine) Marshal(v interface{}) ([]byte, error) {
+	return jsonv2.Marshal(v)
+}
+
+func (jsonV2Engine) Decode(r io.Reader, v interface{}, opts JSONDecodeOptions) error {
+	options := []jsonv2.Options{jsonv2.RejectUnknownMembers(!opts.AllowUnknownFields)}
+	if opts.UseNumber {
+		options = append(options, jsonv2.WithUnmarshalers(jsonv2.UnmarshalFromFunc(decodeNumberAsJSONNumber)))
+	}
+
+	dec := jsontext.NewDecoder(r)
+	if err := jsonv2.UnmarshalDecode(dec, v, options...); err != nil {
+		return err
+	}
+	if _, err := dec.ReadToken(); err != io.EOF {
+		return ErrMultipleJSONValues
+	}
+	return nil
+}
+
+// decodeNumberAsJSONNumber reproduz json.Decoder.UseNumber: números lidos em
+// interface{} viram json.Number em vez de float64.
+func decodeNumberAsJSONNumber(dec *jsontext.Decoder, v *interface{}) error {
+	if dec.PeekKind() != '0' {
+		return errors.ErrUnsupported
+	}
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	*v = json.Number(tok.String())
+	return nil
+}
+
+// jsonV2DecodeError converte os erros de encoding/json/v2 nas mensagens
+// amigáveis do toolkit, retornando nil para os demais erros.
+func jsonV2DecodeError(err error, maxBytes int) error {
+	var maxBytesError *http.MaxBytesError
+	var syntacticError *jsontext.SyntacticError
+	var semanticError *jsonv2.SemanticError
+
+	switch {
+	case errors.As(err, &maxBytesError):
+		return newRequestError(ErrBodyTooLarge, "body must not be larger than %d bytes", maxBytes)
+	case errors.Is(err, jsontext.ErrDuplicateName) && errors.As(err, &syntacticError):
+		return newRequestError(ErrDuplicateJSONKey, "body contains duplicate key %q", jsonPointerPath(syntacticError.JSONPointer))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return newRequestError(ErrBadlyFormedJSON, "body contains badly-formed JSON")
+	case errors.As(err, &syntacticError):
+		return newRequestError(ErrBadlyFormedJSON, "body contains badly-formed JSON (at character %d)", syntacticError.ByteOffset)
+	case errors.Is(err, jsonv2.ErrUnknownName) && errors.As(err, &semanticError):
+		return newRequestError(ErrUnknownField, "body contains unknown field %q", jsonPointerPath(semanticError.JSONPointer))
+	case errors.As(err, &semanticError) && semanticError.JSONKind != 0:
+		if semanticError.JSONPointer != "" {
+			return newRequestError(ErrIncorrectJSONType, "body contains incorrect JSON type for field %q", jsonPointerPath(semanticError.JSONPointer))
+		}
+		return newRequestError(ErrIncorrectJSONType, "body contains incorrect JSON type (at character %d)", semanticError.ByteOffset)
+	}
+	return nil
+}
+
+// jsonPointerPath converte um JSON Pointer como "/endereco/rua" para o formato
+// "endereco.rua" usado nas mensagens de encoding/json.
+func jsonPointerPath(p jsontext.Pointer) string {
+	var names []string
+	for name := range p.Tokens() {
+		names = append(names, name)
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,21 @@
+//go:build !goexperiment.jsonv2 || !go1.27
+
+package toolkit
+
+import "io"
+
+// jsonV2Engine substitui o engine encoding/json/v2 quando o pacote não está
+// disponível (antes do Go 1.27 ou com GOEXPERIMENT=nojsonv2).
+type jsonV2Engine struct{}
+
+func (jsonV2Engine) Marshal(v interface{}) ([]byte, error) {
+	return nil, ErrJSONv2Unavailable
+}
+
+func (jsonV2Engine) Decode(r io.Reader, v interface{}, opts JSONDecodeOptions) error {
+	return ErrJSONv2Unavailable
+}
+
+func jsonV2DecodeError(err error, maxBytes int) error {
+	return nil
+}
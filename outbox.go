@@ -0,0 +1,383 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDeliveryNotFound é retornado quando a entrega consultada não existe no OutboxStore.
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// DeliveryStatus é o estado de uma entrega do Outbox.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending aguarda a próxima tentativa de envio.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered foi aceita pelo destino com uma resposta 2xx.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead esgotou as tentativas ou foi recusada pelo destino.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery é um envio de JSON registrado no Outbox.
+type Delivery struct {
+	ID             string          `json:"id"`
+	URL            string          `json:"url"`
+	Payload        json.RawMessage `json:"payload"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// OutboxStore persiste as entregas do Outbox. As implementações devem ser
+// seguras para uso concorrente e guardar cópias das entregas recebidas.
+type OutboxStore interface {
+	// Save insere ou atualiza a entrega.
+	Save(ctx context.Context, d *Delivery) error
+
+	// Get retorna a entrega com o ID informado ou ErrDeliveryNotFound.
+	Get(ctx context.Context, id string) (*Delivery, error)
+
+	// List retorna as entregas com o status informado, da mais antiga para a
+	// mais recente. Com status vazio, retorna todas.
+	List(ctx context.Context, status DeliveryStatus) ([]*Delivery, error)
+
+	// Due retorna até limit entregas pendentes com NextAttemptAt até now, da
+	// mais atrasada para a mais recente.
+	Due(ctx context.Context, now time.Time, limit int) ([]*Delivery, error)
+
+	// Purge remove as entregas concluídas (DeliveryDelivered e DeliveryDead)
+	// atualizadas antes de before e retorna quantas foram removidas.
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+// Outbox entrega JSON a endpoints remotos com garantia de pelo menos uma
+// entrega. Cada envio é persistido no Store antes da primeira tentativa e
+// enviado por um conjunto de workers, com novas tentativas e espera
+// exponencial, até receber uma resposta 2xx. Após MaxAttempts tentativas, ou
+// se o destino recusar o envio com um 4xx (exceto 408 e 429, e um 401 isolado,
+// repetido com credenciais renovadas), a entrega vai para DeliveryDead.
+//
+// Como uma entrega pode ser repetida (por exemplo, se o processo parar antes
+// de registrar o resultado), todas as tentativas levam o mesmo cabeçalho
+// Idempotency-Key, que o destino pode usar para descartar duplicatas.
+//
+// As chamadas usam RemoteTimeout, RetryPolicy, RateLimiter, CircuitBreaker e
+// RemoteAuthenticator de Tools. Um Store só deve ser usado por um Outbox de
+// cada vez.
+type Outbox struct {
+	Store OutboxStore
+
+	// Workers é o número de entregas feitas em paralelo (4 por padrão).
+	Workers int
+
+	// MaxAttempts é o total de tentativas de cada entrega (5 por padrão).
+	MaxAttempts int
+
+	// BaseDelay e MaxDelay definem a espera entre tentativas, dobrada a cada
+	// falha (1 segundo e 5 minutos por padrão). O cabeçalho Retry-After da
+	// resposta, quando presente, substitui a espera calculada.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// PollInterval é o intervalo de busca de entregas pendentes no Store
+	// (1 segundo por padrão).
+	PollInterval time.Duration
+
+	// HTTPClient é o cliente usado nas entregas. Se nil, é usado um cliente
+	// com o timeout RemoteTimeout.
+	HTTPClient *http.Client
+
+	// OnDeadLetter é chamado quando uma entrega vai para DeliveryDead.
+	OnDeadLetter func(d *Delivery)
+
+	tools    *Tools
+	mu       sync.Mutex
+	inflight map[string]bool
+	wake     chan struct{}
+}
+
+// NewOutbox cria um Outbox que persiste as entregas em store.
+func (t *Tools) NewOutbox(store OutboxStore) *Outbox {
+	return &Outbox{Store: store, tools: t}
+}
+
+// Enqueue registra o envio de data, codificado em JSON, para uri. A entrega é
+// feita em segundo plano por Run; use Status para acompanhá-la.
+func (o *Outbox) Enqueue(ctx context.Context, uri string, data interface{}) (*Delivery, error) {
+	payload, err := o.toolsOrDefault().jsonEngine().Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	id := rand.Text()
+	d := &Delivery{
+		ID:             id,
+		URL:            uri,
+		Payload:        payload,
+		IdempotencyKey: id,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := o.Store.Save(ctx, d); err != nil {
+		return nil, err
+	}
+	o.notify()
+	return d, nil
+}
+
+// Status retorna a entrega com o ID informado.
+func (o *Outbox) Status(ctx context.Context, id string) (*Delivery, error) {
+	return o.Store.Get(ctx, id)
+}
+
+// List retorna as entregas com o status informado ou, com status vazio, todas.
+func (o *Outbox) List(ctx context.Context, status DeliveryStatus) ([]*Delivery, error) {
+	return o.Store.List(ctx, status)
+}
+
+// Retry devolve uma entrega em DeliveryDead para a fila, zerando as tentativas.
+func (o *Outbox) Retry(ctx context.Context, id string) error {
+	d, err := o.Store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if d.Status != DeliveryDead {
+		return fmt.Errorf("delivery %s is %s, not %s", id, d.Status, DeliveryDead)
+	}
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	d.UpdatedAt = d.NextAttemptAt
+	if err := o.Store.Save(ctx, d); err != nil {
+		return err
+	}
+	o.notify()
+	return nil
+}
+
+// Purge remove do Store as entregas concluídas (entregues ou em dead letter)
+// atualizadas antes de before. As entregas pendentes nunca são removidas.
+func (o *Outbox) Purge(ctx context.Context, before time.Time) (int, error) {
+	return o.Store.Purge(ctx, before)
+}
+
+// Run entrega as pendências do Store até ctx ser cancelado e então aguarda o
+// fim das entregas em andamento. Entregas interrompidas pelo cancelamento
+// continuam pendentes, sem contar a tentativa. Erros do Store ao buscar
+// pendências são retornados.
+func (o *Outbox) Run(ctx context.Context) error {
+	o.mu.Lock()
+	if o.wake == nil {
+		o.wake = make(chan struct{}, 1)
+	}
+	if o.inflight == nil {
+		o.inflight = make(map[string]bool)
+	}
+	wake := o.wake
+	o.mu.Unlock()
+
+	workers := o.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	pollInterval := o.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	jobs := make(chan *Delivery)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for d := range jobs {
+				o.deliver(ctx, d)
+				o.mu.Lock()
+				delete(o.inflight, d.ID)
+				o.mu.Unlock()
+				o.notify()
+			}
+		})
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		due, err := o.Store.Due(ctx, time.Now(), workers*2)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, d := range due {
+			o.mu.Lock()
+			busy := o.inflight[d.ID]
+			o.inflight[d.ID] = true
+			o.mu.Unlock()
+			if busy {
+				continue
+			}
+			select {
+			case jobs <- d:
+			case <-ctx.Done():
+				o.mu.Lock()
+				delete(o.inflight, d.ID)
+				o.mu.Unlock()
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// notify acorda o laço de Run para buscar novas pendências.
+func (o *Outbox) notify() {
+	o.mu.Lock()
+	if o.wake == nil {
+		o.wake = make(chan struct{}, 1)
+	}
+	wake := o.wake
+	o.mu.Unlock()
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) toolsOrDefault() *Tools {
+	if o.tools == nil {
+		return &Tools{}
+	}
+	return o.tools
+}
+
+// deliver faz uma tentativa de entrega de d e registra o resultado no Store.
+func (o *Outbox) deliver(ctx context.Context, d *Delivery) {
+	t := o.toolsOrDefault()
+
+	resp, err := o.send(ctx, t, d)
+	if ctx.Err() != nil {
+		// Interrompida pelo encerramento do Outbox: a entrega continua pendente.
+		return
+	}
+
+	prevStatus := d.LastStatusCode
+	d.Attempts++
+	d.UpdatedAt = time.Now()
+	d.LastStatusCode = 0
+	d.LastError = ""
+	if resp != nil {
+		d.LastStatusCode = resp.StatusCode
+	}
+
+	switch {
+	case err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		d.Status = DeliveryDelivered
+	case err == nil && rejectedDelivery(resp.StatusCode, prevStatus):
+		d.Status = DeliveryDead
+		d.LastError = fmt.Sprintf("remote responded with status %d", resp.StatusCode)
+	default:
+		if err != nil {
+			d.LastError = err.Error()
+		} else {
+			d.LastError = fmt.Sprintf("remote responded with status %d", resp.StatusCode)
+		}
+		maxAttempts := o.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = 5
+		}
+		if d.Attempts >= maxAttempts {
+			d.Status = DeliveryDead
+			break
+		}
+		d.NextAttemptAt = d.UpdatedAt.Add(o.retryDelay(d.Attempts, resp))
+	}
+
+	// O resultado é gravado mesmo que ctx seja cancelado em seguida.
+	if err := o.Store.Save(context.WithoutCancel(ctx), d); err != nil {
+		// Sem o registro, a entrega continua pendente no Store e será repetida.
+		return
+	}
+	if d.Status == DeliveryDead && o.OnDeadLetter != nil {
+		o.OnDeadLetter(d)
+	}
+}
+
+// rejectedDelivery indica se a resposta recusa a entrega em definitivo: um 4xx
+// que não seja 408 nem 429. Um 401 só é definitivo se repetido, pois doRemote
+// descarta o token em cache e a próxima tentativa usa credenciais novas.
+func rejectedDelivery(status, prevStatus int) bool {
+	switch {
+	case status < 400 || status > 499:
+		return false
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return false
+	case status == http.StatusUnauthorized:
+		return prevStatus == http.StatusUnauthorized
+	}
+	return true
+}
+
+// send envia o payload de d e descarta o corpo da resposta.
+func (o *Outbox) send(ctx context.Context, t *Tools, d *Delivery) (*http.Response, error) {
+	ctx = WithIdempotencyKey(ctx, d.IdempotencyKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := o.HTTPClient
+	if client == nil {
+		client = t.remoteClient()
+	}
+	resp, err := t.doRemote(client, req, t.RemoteAuthenticator)
+	if err != nil {
+		return nil, err
+	}
+	drainAndClose(resp.Body)
+	return resp, nil
+}
+
+// retryDelay retorna a espera antes da próxima tentativa, usando o cálculo de
+// RetryPolicy com os limites do Outbox.
+func (o *Outbox) retryDelay(attempt int, resp *http.Response) time.Duration {
+	policy := RetryPolicy{BaseDelay: o.BaseDelay, MaxDelay: o.MaxDelay}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = time.Second
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 5 * time.Minute
+	}
+	// Um Retry-After maior que MaxDelay é respeitado.
+	d, _ := policy.delay(attempt, resp)
+	return d
+}
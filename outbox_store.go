@@ -0,0 +1,386 @@
+package toolkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryOutboxStore guarda as entregas do Outbox em memória. As entregas são
+// perdidas quando o processo termina; use-o em testes ou quando a
+// durabilidade não for necessária.
+type MemoryOutboxStore struct {
+	mu         sync.Mutex
+	deliveries map[string]*Delivery
+}
+
+// NewMemoryOutboxStore cria um MemoryOutboxStore vazio.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{deliveries: make(map[string]*Delivery)}
+}
+
+func (s *MemoryOutboxStore) Save(ctx context.Context, d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deliveries == nil {
+		s.deliveries = make(map[string]*Delivery)
+	}
+	s.deliveries[d.ID] = d.clone()
+	return nil
+}
+
+func (s *MemoryOutboxStore) Get(ctx context.Context, id string) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", id, ErrDeliveryNotFound)
+	}
+	return d.clone(), nil
+}
+
+func (s *MemoryOutboxStore) List(ctx context.Context, status DeliveryStatus) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*Delivery
+	for _, d := range s.deliveries {
+		if status == "" || d.Status == status {
+			list = append(list, d.clone())
+		}
+	}
+	sortDeliveries(list, func(d *Delivery) time.Time { return d.CreatedAt })
+	return list, nil
+}
+
+func (s *MemoryOutboxStore) Due(ctx context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*Delivery
+	for _, d := range s.deliveries {
+		if d.Status == DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d.clone())
+		}
+	}
+	return limitDeliveries(due, limit), nil
+}
+
+func (s *MemoryOutboxStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := 0
+	for id, d := range s.deliveries {
+		if d.Status != DeliveryPending && d.UpdatedAt.Before(before) {
+			delete(s.deliveries, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// FileOutboxStore guarda cada entrega do Outbox em um arquivo JSON: as
+// pendentes em Dir/pending e as concluídas (entregues ou em dead letter) em
+// Dir/done, de modo que Due lê apenas as pendentes. As gravações são atômicas
+// (arquivo temporário, rename e fsync do diretório), de modo que as entregas
+// sobrevivem à reinicialização do processo ou a uma queda do sistema.
+//
+// Arquivos que não podem ser lidos como JSON são renomeados com a extensão
+// ".corrupt", informados a OnCorrupt e ignorados, sem interromper o Outbox.
+type FileOutboxStore struct {
+	Dir string
+
+	// OnCorrupt, se informado, é chamado com o caminho original e o erro de
+	// cada arquivo corrompido colocado em quarentena.
+	OnCorrupt func(path string, err error)
+
+	mu sync.Mutex
+}
+
+// Subdiretórios de FileOutboxStore.
+const (
+	outboxPendingDir = "pending"
+	outboxDoneDir    = "done"
+)
+
+// NewFileOutboxStore cria um FileOutboxStore em dir, criando os diretórios se
+// eles não existirem.
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	for _, sub := range []string{outboxPendingDir, outboxDoneDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &FileOutboxStore{Dir: dir}, nil
+}
+
+func (s *FileOutboxStore) Save(ctx context.Context, d *Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, other := outboxDoneDir, outboxPendingDir
+	if d.Status == DeliveryPending {
+		target, other = outboxPendingDir, outboxDoneDir
+	}
+	if err := writeFileAtomic(filepath.Join(s.Dir, target), d.ID+".json", data); err != nil {
+		return err
+	}
+	// A cópia no outro diretório só é removida após a nova estar gravada; se
+	// o processo parar entre os dois passos, a mais recente prevalece.
+	err = os.Remove(s.path(other, d.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Join(s.Dir, other))
+}
+
+func (s *FileOutboxStore) Get(ctx context.Context, id string) (*Delivery, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("%s: %w", id, ErrDeliveryNotFound)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *Delivery
+	for _, sub := range []string{outboxPendingDir, outboxDoneDir} {
+		d, err := s.read(sub, id+".json")
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if found == nil || d.UpdatedAt.After(found.UpdatedAt) {
+			found = d
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s: %w", id, ErrDeliveryNotFound)
+	}
+	return found, nil
+}
+
+func (s *FileOutboxStore) List(ctx context.Context, status DeliveryStatus) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []*Delivery
+	if status == "" || status == DeliveryPending {
+		pending, err := s.loadPending()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, pending...)
+	}
+	if status != DeliveryPending {
+		done, err := s.load(outboxDoneDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range done {
+			if status == "" || d.Status == status {
+				list = append(list, d)
+			}
+		}
+	}
+	sortDeliveries(list, func(d *Delivery) time.Time { return d.CreatedAt })
+	return list, nil
+}
+
+func (s *FileOutboxStore) Due(ctx context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, err := s.loadPending()
+	if err != nil {
+		return nil, err
+	}
+	var due []*Delivery
+	for _, d := range pending {
+		if !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return limitDeliveries(due, limit), nil
+}
+
+func (s *FileOutboxStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	done, err := s.load(outboxDoneDir)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, d := range done {
+		if !d.UpdatedAt.Before(before) {
+			continue
+		}
+		if err := os.Remove(s.path(outboxDoneDir, d.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return purged, err
+		}
+		purged++
+	}
+	if purged > 0 {
+		return purged, syncDir(filepath.Join(s.Dir, outboxDoneDir))
+	}
+	return 0, nil
+}
+
+func (s *FileOutboxStore) path(sub, id string) string {
+	return filepath.Join(s.Dir, sub, id+".json")
+}
+
+// loadPending lê as entregas pendentes, descartando as que já têm uma versão
+// concluída mais recente (restos de um Save interrompido).
+func (s *FileOutboxStore) loadPending() ([]*Delivery, error) {
+	pending, err := s.load(outboxPendingDir)
+	if err != nil {
+		return nil, err
+	}
+	list := pending[:0]
+	for _, d := range pending {
+		done, err := s.read(outboxDoneDir, d.ID+".json")
+		if err == nil && done.UpdatedAt.After(d.UpdatedAt) {
+			_ = os.Remove(s.path(outboxPendingDir, d.ID))
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// load lê as entregas do subdiretório sub. Arquivos corrompidos são colocados
+// em quarentena e ignorados. Deve ser chamado com o mutex travado.
+func (s *FileOutboxStore) load(sub string) ([]*Delivery, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Dir, sub))
+	if err != nil {
+		return nil, err
+	}
+	var list []*Delivery
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		d, err := s.read(sub, name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// read lê o arquivo name de sub. Um arquivo corrompido é colocado em
+// quarentena e resulta em os.ErrNotExist.
+func (s *FileOutboxStore) read(sub, name string) (*Delivery, error) {
+	path := filepath.Join(s.Dir, sub, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d Delivery
+	if err := json.Unmarshal(data, &d); err != nil || d.ID == "" {
+		if err == nil {
+			err = errors.New("delivery has no id")
+		}
+		s.quarantine(path, err)
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return &d, nil
+}
+
+// quarantine renomeia um arquivo corrompido para "<nome>.corrupt".
+func (s *FileOutboxStore) quarantine(path string, err error) {
+	_ = os.Rename(path, path+".corrupt")
+	if s.OnCorrupt != nil {
+		s.OnCorrupt(path, err)
+	}
+}
+
+// writeFileAtomic grava data em dir/name por meio de um arquivo temporário,
+// com fsync do arquivo e do diretório, para que a gravação sobreviva a quedas.
+func writeFileAtomic(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, ".delivery-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir faz o fsync de um diretório, persistindo criações, renomeações e
+// remoções de arquivos. Sistemas que não permitem fsync de diretórios, como o
+// Windows, são ignorados.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+	return nil
+}
+
+func (d *Delivery) clone() *Delivery {
+	c := *d
+	c.Payload = slices.Clone(d.Payload)
+	return &c
+}
+
+// sortDeliveries ordena list pelo instante retornado por at e, no empate, pelo ID.
+func sortDeliveries(list []*Delivery, at func(d *Delivery) time.Time) {
+	slices.SortFunc(list, func(a, b *Delivery) int {
+		if c := at(a).Compare(at(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// limitDeliveries ordena as entregas pendentes pela próxima tentativa e
+// retorna no máximo limit delas.
+func limitDeliveries(due []*Delivery, limit int) []*Delivery {
+	sortDeliveries(due, func(d *Delivery) time.Time { return d.NextAttemptAt })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due
+}
@@ -0,0 +1,134 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutboxStores(t *testing.T) {
+	fileStore, err := NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	stores := map[string]OutboxStore{
+		"memória": NewMemoryOutboxStore(),
+		"arquivo": fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now().Truncate(time.Millisecond)
+			deliveries := []*Delivery{
+				{ID: "a", Status: DeliveryPending, Payload: []byte(`{"n":1}`), NextAttemptAt: now.Add(-time.Minute), CreatedAt: now.Add(-3 * time.Second)},
+				{ID: "b", Status: DeliveryPending, NextAttemptAt: now.Add(-2 * time.Minute), CreatedAt: now.Add(-2 * time.Second)},
+				{ID: "c", Status: DeliveryPending, NextAttemptAt: now.Add(time.Minute), CreatedAt: now.Add(-time.Second)},
+				{ID: "d", Status: DeliveryDelivered, NextAttemptAt: now.Add(-time.Minute), CreatedAt: now},
+			}
+			for _, d := range deliveries {
+				if err := store.Save(ctx, d); err != nil {
+					t.Fatalf("erro inesperado: %v", err)
+				}
+			}
+
+			got, err := store.Get(ctx, "a")
+			if err != nil || string(got.Payload) != `{"n":1}` || !got.NextAttemptAt.Equal(deliveries[0].NextAttemptAt) {
+				t.Errorf("entrega lida incorretamente: %+v %v", got, err)
+			}
+			got.Payload[0] = 'x'
+			if again, _ := store.Get(ctx, "a"); string(again.Payload) != `{"n":1}` {
+				t.Error("o store deveria guardar uma cópia da entrega")
+			}
+
+			if _, err := store.Get(ctx, "z"); !errors.Is(err, ErrDeliveryNotFound) {
+				t.Errorf("esperado ErrDeliveryNotFound, mas obteve %v", err)
+			}
+
+			due, _ := store.Due(ctx, now, 10)
+			if len(due) != 2 || due[0].ID != "b" || due[1].ID != "a" {
+				t.Errorf("pendências incorretas: %v", deliveryIDs(due))
+			}
+			if due, _ := store.Due(ctx, now, 1); len(due) != 1 {
+				t.Errorf("o limite deveria ser respeitado: %v", deliveryIDs(due))
+			}
+
+			all, _ := store.List(ctx, "")
+			if ids := deliveryIDs(all); len(ids) != 4 || ids[0] != "a" || ids[3] != "d" {
+				t.Errorf("lista incorreta: %v", ids)
+			}
+			delivered, _ := store.List(ctx, DeliveryDelivered)
+			if len(delivered) != 1 || delivered[0].ID != "d" {
+				t.Errorf("filtro por status incorreto: %v", deliveryIDs(delivered))
+			}
+
+			got, _ = store.Get(ctx, "a")
+			got.Status = DeliveryDead
+			if err := store.Save(ctx, got); err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if due, _ := store.Due(ctx, now, 10); len(due) != 1 || due[0].ID != "b" {
+				t.Errorf("a atualização deveria substituir a entrega: %v", deliveryIDs(due))
+			}
+
+			if n, err := store.Purge(ctx, now); err != nil || n != 2 {
+				t.Errorf("esperado remover 2 entregas concluídas, mas removeu %d (%v)", n, err)
+			}
+			if all, _ := store.List(ctx, ""); len(all) != 2 {
+				t.Errorf("as entregas pendentes não deveriam ser removidas: %v", deliveryIDs(all))
+			}
+		})
+	}
+}
+
+func TestFileOutboxStore_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileOutboxStore(dir)
+	var corrupted []string
+	store.OnCorrupt = func(path string, err error) {
+		corrupted = append(corrupted, filepath.Base(path))
+	}
+
+	ctx := context.Background()
+	if err := store.Save(ctx, &Delivery{ID: "a", Status: DeliveryPending}); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	bad := filepath.Join(dir, "pending", "b.json")
+	if err := os.WriteFile(bad, []byte(`{"id": "b",`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := store.Due(ctx, time.Now(), 10)
+	if err != nil || len(due) != 1 || due[0].ID != "a" {
+		t.Errorf("o arquivo corrompido deveria ser ignorado: %v %v", deliveryIDs(due), err)
+	}
+	if len(corrupted) != 1 || corrupted[0] != "b.json" {
+		t.Errorf("OnCorrupt deveria receber o arquivo corrompido: %v", corrupted)
+	}
+	if _, err := os.Stat(bad + ".corrupt"); err != nil {
+		t.Errorf("o arquivo corrompido deveria ser renomeado: %v", err)
+	}
+	if _, err := store.Due(ctx, time.Now(), 10); err != nil || len(corrupted) != 1 {
+		t.Errorf("o arquivo em quarentena não deveria ser lido novamente: %v %v", corrupted, err)
+	}
+}
+
+func TestFileOutboxStore_Get_InvalidID(t *testing.T) {
+	store, _ := NewFileOutboxStore(t.TempDir())
+	for _, id := range []string{"", "../a", ".delivery-1"} {
+		if _, err := store.Get(context.Background(), id); !errors.Is(err, ErrDeliveryNotFound) {
+			t.Errorf("%q: esperado ErrDeliveryNotFound, mas obteve %v", id, err)
+		}
+	}
+}
+
+func deliveryIDs(list []*Delivery) []string {
+	ids := make([]string, len(list))
+	for i, d := range list {
+		ids[i] = d.ID
+	}
+	return ids
+}
@@ -0,0 +1,247 @@
+package toolkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runOutbox executa o Outbox em segundo plano e retorna a função que o encerra.
+func runOutbox(t *testing.T, o *Outbox) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- o.Run(ctx) }()
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("erro inesperado em Run: %v", err)
+		}
+	}
+}
+
+// waitDelivery aguarda a entrega id chegar ao status informado.
+func waitDelivery(t *testing.T, o *Outbox, id string, status DeliveryStatus) *Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		d, err := o.Status(context.Background(), id)
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if d.Status == status {
+			return d
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("esperado status %s, mas a entrega está %s: %+v", status, d.Status, d)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("entrega com chave de idempotência", func(t *testing.T) {
+		var mu sync.Mutex
+		var keys []string
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			body = string(b)
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		var testTools Tools
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, err := o.Enqueue(context.Background(), server.URL, map[string]string{"evento": "criado"})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		got := waitDelivery(t, o, d.ID, DeliveryDelivered)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(keys) != 1 || keys[0] != d.IdempotencyKey || body != `{"evento":"criado"}` {
+			t.Errorf("envio incorreto: chaves %v, corpo %s", keys, body)
+		}
+		if got.Attempts != 1 || got.LastStatusCode != http.StatusAccepted {
+			t.Errorf("registro incorreto: %+v", got)
+		}
+	})
+
+	t.Run("novas tentativas até o sucesso", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		var testTools Tools
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		o.BaseDelay = time.Millisecond
+		o.PollInterval = 5 * time.Millisecond
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, _ := o.Enqueue(context.Background(), server.URL, nil)
+		if got := waitDelivery(t, o, d.ID, DeliveryDelivered); got.Attempts != 3 {
+			t.Errorf("esperado 3 tentativas, mas obteve %d", got.Attempts)
+		}
+	})
+
+	t.Run("dead letter após MaxAttempts e Retry", func(t *testing.T) {
+		var fail atomic.Bool
+		fail.Store(true)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fail.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		deadLetters := make(chan *Delivery, 1)
+		var testTools Tools
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		o.MaxAttempts = 2
+		o.BaseDelay = time.Millisecond
+		o.PollInterval = 5 * time.Millisecond
+		o.OnDeadLetter = func(d *Delivery) { deadLetters <- d }
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, _ := o.Enqueue(context.Background(), server.URL, nil)
+		got := waitDelivery(t, o, d.ID, DeliveryDead)
+		if got.Attempts != 2 || got.LastError != "remote responded with status 500" {
+			t.Errorf("registro incorreto: %+v", got)
+		}
+		if dl := <-deadLetters; dl.ID != d.ID {
+			t.Errorf("OnDeadLetter recebeu a entrega errada: %s", dl.ID)
+		}
+		if dead, _ := o.List(context.Background(), DeliveryDead); len(dead) != 1 {
+			t.Errorf("esperada 1 entrega em dead letter, mas obteve %d", len(dead))
+		}
+
+		fail.Store(false)
+		if err := o.Retry(context.Background(), d.ID); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		waitDelivery(t, o, d.ID, DeliveryDelivered)
+		if err := o.Retry(context.Background(), d.ID); err == nil {
+			t.Error("Retry deveria falhar para entregas concluídas")
+		}
+	})
+
+	t.Run("4xx vai direto para dead letter", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer server.Close()
+
+		var testTools Tools
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, _ := o.Enqueue(context.Background(), server.URL, nil)
+		waitDelivery(t, o, d.ID, DeliveryDead)
+		if calls.Load() != 1 {
+			t.Errorf("esperada 1 chamada, mas obteve %d", calls.Load())
+		}
+	})
+
+	t.Run("401 repetido com token renovado", func(t *testing.T) {
+		var tokens atomic.Int32
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := tokens.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600}`, n)
+		}))
+		defer tokenServer.Close()
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		defer server.Close()
+
+		testTools := Tools{RemoteAuthenticator: &OAuth2ClientCredentials{TokenURL: tokenServer.URL, ClientID: "id", ClientSecret: "segredo"}}
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		o.BaseDelay = time.Millisecond
+		o.PollInterval = 5 * time.Millisecond
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, _ := o.Enqueue(context.Background(), server.URL, nil)
+		got := waitDelivery(t, o, d.ID, DeliveryDelivered)
+		if got.Attempts != 2 || tokens.Load() != 2 {
+			t.Errorf("esperada uma nova tentativa com token renovado: %+v (%d tokens)", got, tokens.Load())
+		}
+	})
+
+	t.Run("401 repetido vai para dead letter", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		var testTools Tools
+		o := testTools.NewOutbox(NewMemoryOutboxStore())
+		o.BaseDelay = time.Millisecond
+		o.PollInterval = 5 * time.Millisecond
+		stop := runOutbox(t, o)
+		defer stop()
+
+		d, _ := o.Enqueue(context.Background(), server.URL, nil)
+		waitDelivery(t, o, d.ID, DeliveryDead)
+		if calls.Load() != 2 {
+			t.Errorf("esperadas 2 chamadas, mas obteve %d", calls.Load())
+		}
+	})
+
+	t.Run("pendências sobrevivem à reinicialização", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		var testTools Tools
+		store, err := NewFileOutboxStore(dir)
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		d, _ := testTools.NewOutbox(store).Enqueue(context.Background(), server.URL, map[string]int{"n": 1})
+
+		reopened, _ := NewFileOutboxStore(dir)
+		o := testTools.NewOutbox(reopened)
+		stop := runOutbox(t, o)
+		defer stop()
+
+		waitDelivery(t, o, d.ID, DeliveryDelivered)
+		if calls.Load() != 1 {
+			t.Errorf("esperada 1 chamada, mas obteve %d", calls.Load())
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package toolkit
+
+import (
+	"encoding/xml"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Pagination descreve a página retornada em JSONResponse. Em paginação por
+// offset são usados Page, PerPage, Total e TotalPages; em paginação por
+// cursor, PerPage, Next e Prev.
+type Pagination struct {
+	Page       int    `json:"page,omitempty" xml:"page,omitempty"`
+	PerPage    int    `json:"per_page" xml:"per_page"`
+	Total      *int   `json:"total,omitempty" xml:"total,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty" xml:"total_pages,omitempty"`
+	Next       string `json:"next,omitempty" xml:"next,omitempty"`
+	Prev       string `json:"prev,omitempty" xml:"prev,omitempty"`
+}
+
+// MarshalXML serializa JSONResponse em um elemento <response>, com os campos
+// nomeados como no JSON. Links e Meta, que encoding/xml não suporta, viram
+// elementos aninhados (veja XMLCodec).
+func (r JSONResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type response struct {
+		Error      bool        `xml:"error"`
+		Message    string      `xml:"message"`
+		Data       *xmlValue   `xml:"data,omitempty"`
+		Pagination *Pagination `xml:"pagination,omitempty"`
+		Links      *xmlValue   `xml:"links,omitempty"`
+		RequestID  string      `xml:"request_id,omitempty"`
+		Meta       *xmlValue   `xml:"meta,omitempty"`
+	}
+	out := response{Error: r.Error, Message: r.Message, Pagination: r.Pagination, RequestID: r.RequestID}
+	if r.Data != nil {
+		out.Data = &xmlValue{r.Data}
+	}
+	if len(r.Links) > 0 {
+		out.Links = &xmlValue{r.Links}
+	}
+	if len(r.Meta) > 0 {
+		out.Meta = &xmlValue{r.Meta}
+	}
+	return e.EncodeElement(out, xml.StartElement{Name: xml.Name{Local: "response"}})
+}
+
+// PageParams são os parâmetros de paginação lidos da query string.
+type PageParams struct {
+	Page    int
+	PerPage int
+	Cursor  string
+}
+
+// Offset retorna a quantidade de itens que antecedem a página atual. Páginas
+// cujo offset não cabe em um int, como page=9223372036854775807, retornam
+// math.MaxInt.
+func (p PageParams) Offset() int {
+	if p.Page < 1 || p.PerPage < 1 {
+		return 0
+	}
+	if p.Page-1 > math.MaxInt/p.PerPage {
+		return math.MaxInt
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+// ReadPageParams lê os parâmetros page, per_page e cursor da query string.
+// Sem page, assume a primeira página; sem per_page, usa DefaultPerPage (20 por
+// padrão). per_page é limitado a MaxPerPage (100 por padrão). Valores que não
+// são inteiros positivos retornam ValidationErrors.
+func (t *Tools) ReadPageParams(r *http.Request) (PageParams, error) {
+	query := r.URL.Query()
+	params := PageParams{Page: 1, PerPage: t.defaultPerPage(), Cursor: query.Get("cursor")}
+
+	var errs ValidationErrors
+	if v := query.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			errs = append(errs, &FieldError{Field: "/page", Rule: "min", Message: "must be a positive integer"})
+		} else {
+			params.Page = page
+		}
+	}
+	if v := query.Get("per_page"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil || perPage < 1 {
+			errs = append(errs, &FieldError{Field: "/per_page", Rule: "min", Message: "must be a positive integer"})
+		} else {
+			params.PerPage = min(perPage, t.maxPerPage())
+		}
+	}
+
+	if len(errs) > 0 {
+		return params, errs
+	}
+	return params, nil
+}
+
+func (t *Tools) defaultPerPage() int {
+	if t.DefaultPerPage > 0 {
+		return min(t.DefaultPerPage, t.maxPerPage())
+	}
+	return min(20, t.maxPerPage())
+}
+
+func (t *Tools) maxPerPage() int {
+	if t.MaxPerPage > 0 {
+		return t.MaxPerPage
+	}
+	return 100
+}
+
+// NewPageResponse monta um JSONResponse para paginação por offset, com os
+// metadados da página, os links self, first, last, prev e next e o ID da
+// requisição (cabeçalho RequestIDHeader, "X-Request-ID" por padrão).
+func (t *Tools) NewPageResponse(r *http.Request, data interface{}, total int, p PageParams) *JSONResponse {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage < 1 {
+		p.PerPage = t.defaultPerPage()
+	}
+
+	totalPages := (total + p.PerPage - 1) / p.PerPage
+	pageLink := func(page int) string {
+		return linkWithQuery(r.URL, map[string]string{"page": strconv.Itoa(page), "per_page": strconv.Itoa(p.PerPage)})
+	}
+
+	links := map[string]string{
+		"self":  pageLink(p.Page),
+		"first": pageLink(1),
+		"last":  pageLink(max(totalPages, 1)),
+	}
+	if p.Page > 1 {
+		links["prev"] = pageLink(min(p.Page-1, max(totalPages, 1)))
+	}
+	if p.Page < totalPages {
+		links["next"] = pageLink(p.Page + 1)
+	}
+
+	return &JSONResponse{
+		Data: data,
+		Pagination: &Pagination{
+			Page:       p.Page,
+			PerPage:    p.PerPage,
+			Total:      &total,
+			TotalPages: totalPages,
+		},
+		Links:     links,
+		RequestID: t.requestID(r),
+	}
+}
+
+// NewCursorPageResponse monta um JSONResponse para paginação por cursor. next
+// e prev são os cursores das páginas vizinhas, vazios quando não existirem.
+func (t *Tools) NewCursorPageResponse(r *http.Request, data interface{}, p PageParams, next, prev string) *JSONResponse {
+	if p.PerPage < 1 {
+		p.PerPage = t.defaultPerPage()
+	}
+
+	perPage := strconv.Itoa(p.PerPage)
+	links := map[string]string{"self": r.URL.RequestURI()}
+	if next != "" {
+		links["next"] = linkWithQuery(r.URL, map[string]string{"cursor": next, "per_page": perPage})
+	}
+	if prev != "" {
+		links["prev"] = linkWithQuery(r.URL, map[string]string{"cursor": prev, "per_page": perPage})
+	}
+
+	return &JSONResponse{
+		Data:       data,
+		Pagination: &Pagination{PerPage: p.PerPage, Next: next, Prev: prev},
+		Links:      links,
+		RequestID:  t.requestID(r),
+	}
+}
+
+// WritePage escreve resp com WriteJSON e adiciona o cabeçalho Link (RFC 8288)
+// a partir de resp.Links.
+func (t *Tools) WritePage(w http.ResponseWriter, status int, resp *JSONResponse, headers ...http.Header) error {
+	if link := LinkHeader(resp.Links); link != "" {
+		w.Header().Set("Link", link)
+	}
+	return t.WriteJSON(w, status, resp, headers...)
+}
+
+// linkRelations define a ordem das relações no cabeçalho Link.
+var linkRelations = []string{"self", "first", "prev", "next", "last"}
+
+// LinkHeader formata links no formato do cabeçalho Link da RFC 8288, como
+// `</items?page=2>; rel="next"`. As relações conhecidas vêm primeiro, na ordem
+// self, first, prev, next e last; as demais seguem em ordem alfabética.
+func LinkHeader(links map[string]string) string {
+	var rels []string
+	for _, rel := range linkRelations {
+		if _, ok := links[rel]; ok {
+			rels = append(rels, rel)
+		}
+	}
+	var others []string
+	for rel := range links {
+		if !slices.Contains(linkRelations, rel) {
+			others = append(others, rel)
+		}
+	}
+	slices.Sort(others)
+	rels = append(rels, others...)
+
+	parts := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		parts = append(parts, "<"+links[rel]+`>; rel="`+rel+`"`)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// linkWithQuery retorna o caminho e a query de u com os parâmetros substituídos.
+func linkWithQuery(u *url.URL, params map[string]string) string {
+	query := u.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	if _, ok := params["cursor"]; ok {
+		query.Del("page")
+	}
+	link := url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: query.Encode()}
+	return link.RequestURI()
+}
+
+// requestID retorna o ID da requisição enviado no cabeçalho RequestIDHeader.
+func (t *Tools) requestID(r *http.Request) string {
+	header := t.RequestIDHeader
+	if header == "" {
+		header = "X-Request-ID"
+	}
+	return r.Header.Get(header)
+}
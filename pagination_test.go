@@ -0,0 +1,159 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTools_ReadPageParams(t *testing.T) {
+	testTools := Tools{MaxPerPage: 50}
+
+	testCases := []struct {
+		name          string
+		query         string
+		expected      PageParams
+		expectedError bool
+	}{
+		{name: "valores padrão", query: "", expected: PageParams{Page: 1, PerPage: 20}},
+		{name: "valores informados", query: "?page=3&per_page=10", expected: PageParams{Page: 3, PerPage: 10}},
+		{name: "per_page acima do máximo", query: "?per_page=500", expected: PageParams{Page: 1, PerPage: 50}},
+		{name: "cursor", query: "?cursor=abc", expected: PageParams{Page: 1, PerPage: 20, Cursor: "abc"}},
+		{name: "página inválida", query: "?page=0", expectedError: true},
+		{name: "per_page inválido", query: "?per_page=dez", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/items"+tc.query, nil)
+			params, err := testTools.ReadPageParams(req)
+
+			if tc.expectedError {
+				var verrs ValidationErrors
+				if !errors.As(err, &verrs) {
+					t.Errorf("esperado ValidationErrors, mas obteve %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if params != tc.expected {
+				t.Errorf("parâmetros incorretos: esperado %+v, mas obteve %+v", tc.expected, params)
+			}
+		})
+	}
+}
+
+func TestPageParams_Offset(t *testing.T) {
+	var testTools Tools
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{name: "primeira página", query: "?page=1&per_page=10", expected: 0},
+		{name: "terceira página", query: "?page=3&per_page=10", expected: 20},
+		{name: "página muito grande", query: "?page=9223372036854775807&per_page=100", expected: math.MaxInt},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := testTools.ReadPageParams(httptest.NewRequest("GET", "/items"+tc.query, nil))
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if offset := params.Offset(); offset != tc.expected {
+				t.Errorf("offset incorreto: esperado %d, mas obteve %d", tc.expected, offset)
+			}
+		})
+	}
+}
+
+func TestTools_WritePage(t *testing.T) {
+	var testTools Tools
+
+	req := httptest.NewRequest("GET", "/items?page=2&per_page=10&sort=name", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	params, _ := testTools.ReadPageParams(req)
+
+	resp := testTools.NewPageResponse(req, []string{"a", "b"}, 35, params)
+	rr := httptest.NewRecorder()
+	if err := testTools.WritePage(rr, http.StatusOK, resp); err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+
+	expectedLink := `</items?page=2&per_page=10&sort=name>; rel="self", ` +
+		`</items?page=1&per_page=10&sort=name>; rel="first", ` +
+		`</items?page=1&per_page=10&sort=name>; rel="prev", ` +
+		`</items?page=3&per_page=10&sort=name>; rel="next", ` +
+		`</items?page=4&per_page=10&sort=name>; rel="last"`
+	if link := rr.Header().Get("Link"); link != expectedLink {
+		t.Errorf("cabeçalho Link incorreto:\nesperado %s\nobteve   %s", expectedLink, link)
+	}
+
+	var payload JSONResponse
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("falha ao decodificar corpo JSON: %v", err)
+	}
+	if payload.Pagination == nil || payload.Pagination.Page != 2 || *payload.Pagination.Total != 35 || payload.Pagination.TotalPages != 4 {
+		t.Errorf("paginação incorreta: %+v", payload.Pagination)
+	}
+	if payload.RequestID != "req-123" {
+		t.Errorf("request_id incorreto: esperado 'req-123', mas obteve '%s'", payload.RequestID)
+	}
+}
+
+func TestTools_NewCursorPageResponse(t *testing.T) {
+	var testTools Tools
+
+	req := httptest.NewRequest("GET", "/events?cursor=c1&per_page=5", nil)
+	params, _ := testTools.ReadPageParams(req)
+
+	resp := testTools.NewCursorPageResponse(req, []int{1, 2}, params, "c2", "")
+
+	if resp.Pagination.Next != "c2" || resp.Pagination.Prev != "" || resp.Pagination.Total != nil {
+		t.Errorf("paginação incorreta: %+v", resp.Pagination)
+	}
+	if resp.Links["next"] != "/events?cursor=c2&per_page=5" {
+		t.Errorf("link next incorreto: obteve '%s'", resp.Links["next"])
+	}
+	if _, ok := resp.Links["prev"]; ok {
+		t.Error("não deveria haver link prev na primeira página")
+	}
+}
+
+func TestTools_WriteResponse_PageXML(t *testing.T) {
+	var testTools Tools
+
+	req := httptest.NewRequest("GET", "/items?page=1&per_page=2", nil)
+	req.Header.Set("Accept", "application/xml")
+	req.Header.Set("X-Request-ID", "req-123")
+	params, _ := testTools.ReadPageParams(req)
+
+	resp := testTools.NewPageResponse(req, []string{"a", "b"}, 3, params)
+	resp.Meta = map[string]interface{}{"source": "cache"}
+	rr := httptest.NewRecorder()
+	if err := testTools.WriteResponse(rr, req, http.StatusOK, resp); err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+
+	body := rr.Body.String()
+	for _, expected := range []string{
+		"<response><error>false</error>",
+		"<data><item>a</item><item>b</item></data>",
+		"<pagination><page>1</page><per_page>2</per_page><total>3</total><total_pages>2</total_pages></pagination>",
+		"<next>/items?page=2&amp;per_page=2</next>",
+		"<request_id>req-123</request_id>",
+		"<meta><source>cache</source></meta>",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("o XML deveria conter %s:\n%s", expected, body)
+		}
+	}
+}
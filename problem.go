@@ -0,0 +1,225 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// ProblemDetails representa um documento application/problem+json conforme a
+// RFC 9457 (que substitui a RFC 7807). Membros de extensão ficam em Extensions
+// e são serializados no mesmo nível dos membros padrão.
+type ProblemDetails struct {
+	Type       string                 `json:"type,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Status     int                    `json:"status,omitempty"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// Error permite que um ProblemDetails seja retornado como erro e repassado
+// para ErrorJSON sem perder os seus membros.
+func (p *ProblemDetails) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// MarshalJSON serializa os membros padrão junto com os membros de extensão.
+// Em caso de conflito, os membros padrão prevalecem.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		out[key] = value
+	}
+
+	type problem ProblemDetails
+	std, err := json.Marshal(problem(p))
+	if err != nil {
+		return nil, err
+	}
+	var members map[string]interface{}
+	if err := json.Unmarshal(std, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range members {
+		out[key] = value
+	}
+
+	return json.Marshal(out)
+}
+
+// MarshalXML serializa o documento no formato XML da RFC 9457, com os membros
+// de extensão como elementos no mesmo nível dos membros padrão.
+func (p ProblemDetails) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Space: "urn:ietf:rfc:7807", Local: "problem"}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	members := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"type", p.Type, p.Type == ""},
+		{"title", p.Title, p.Title == ""},
+		{"status", p.Status, p.Status == 0},
+		{"detail", p.Detail, p.Detail == ""},
+		{"instance", p.Instance, p.Instance == ""},
+	}
+	for _, m := range members {
+		if m.empty {
+			continue
+		}
+		if err := e.EncodeElement(m.value, xml.StartElement{Name: xml.Name{Local: m.name}}); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(p.Extensions))
+	for key := range p.Extensions {
+		switch key {
+		case "type", "title", "status", "detail", "instance":
+			// Em caso de conflito, os membros padrão prevalecem.
+		default:
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := e.EncodeElement(xmlValue{p.Extensions[key]}, xmlKeyElement(key)); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalJSON lê os membros padrão e guarda os demais em Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	type problem ProblemDetails
+	var std problem
+	if err := json.Unmarshal(data, &std); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, key := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(members, key)
+	}
+
+	*p = ProblemDetails(std)
+	if len(members) > 0 {
+		p.Extensions = make(map[string]interface{}, len(members))
+		for key, raw := range members {
+			var value interface{}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			p.Extensions[key] = value
+		}
+	}
+	return nil
+}
+
+// problemTypes associa os erros sentinela do toolkit a tipos de problema.
+var problemTypes = []struct {
+	err    error
+	slug   string
+	title  string
+	status int
+}{
+	{err: ErrBodyTooLarge, slug: "body-too-large", title: "Request body too large", status: http.StatusRequestEntityTooLarge},
+	{err: ErrBadlyFormedJSON, slug: "malformed-json", title: "Malformed JSON", status: http.StatusBadRequest},
+	{err: ErrIncorrectJSONType, slug: "invalid-json-type", title: "Invalid JSON type", status: http.StatusBadRequest},
+	{err: ErrEmptyBody, slug: "empty-body", title: "Empty request body", status: http.StatusBadRequest},
+	{err: ErrUnknownField, slug: "unknown-field", title: "Unknown field", status: http.StatusBadRequest},
+	{err: ErrMultipleJSONValues, slug: "multiple-json-values", title: "Multiple JSON values", status: http.StatusBadRequest},
+	{err: ErrDuplicateJSONKey, slug: "duplicate-json-key", title: "Duplicate JSON key", status: http.StatusBadRequest},
+	{err: ErrJSONTooDeep, slug: "json-too-deep", title: "JSON nested too deep", status: http.StatusBadRequest},
+	{err: ErrMalformedBody, slug: "malformed-body", title: "Malformed request body", status: http.StatusBadRequest},
+	{err: ErrUnsupportedMediaType, slug: "unsupported-media-type", title: "Unsupported media type", status: http.StatusUnsupportedMediaType},
+	{err: ErrInvalidSignature, slug: "invalid-signature", title: "Invalid signature", status: http.StatusUnauthorized},
+}
+
+// NewProblem converte um erro em ProblemDetails. Erros do toolkit (corpo muito
+// grande, campo desconhecido, falhas de validação etc.) recebem automaticamente
+// o status, o título e o tipo correspondentes; os demais usam "about:blank" e
+// o status BadRequest. O primeiro status informado, se houver, tem precedência.
+//
+// O tipo é formado por ProblemTypeBaseURI seguido de um identificador como
+// "body-too-large". Sem ProblemTypeBaseURI, o tipo é "about:blank" e o
+// identificador é enviado no membro de extensão "code".
+func (t *Tools) NewProblem(err error, status ...int) *ProblemDetails {
+	var existing *ProblemDetails
+	if errors.As(err, &existing) {
+		p := *existing
+		if len(status) > 0 {
+			p.Status = status[0]
+		}
+		if p.Status == 0 {
+			p.Status = http.StatusBadRequest
+		}
+		if p.Type == "" {
+			p.Type = "about:blank"
+		}
+		return &p
+	}
+
+	p := &ProblemDetails{Status: http.StatusBadRequest, Detail: err.Error()}
+
+	var slug, title string
+	for _, pt := range problemTypes {
+		if errors.Is(err, pt.err) {
+			slug, title, p.Status = pt.slug, pt.title, pt.status
+			break
+		}
+	}
+
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		slug, title, p.Status = "validation-failed", "Validation failed", http.StatusUnprocessableEntity
+		p.Extensions = map[string]interface{}{"errors": verrs}
+	}
+
+	if len(status) > 0 {
+		p.Status = status[0]
+	}
+
+	switch {
+	case slug != "" && t.ProblemTypeBaseURI != "":
+		p.Type = t.ProblemTypeBaseURI + slug
+		p.Title = title
+	default:
+		p.Type = "about:blank"
+		p.Title = http.StatusText(p.Status)
+	}
+	if slug != "" {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["code"] = slug
+	}
+
+	return p
+}
+
+// WriteProblem escreve um documento application/problem+json com o status do
+// próprio problema (ou BadRequest, se não informado).
+func (t *Tools) WriteProblem(w http.ResponseWriter, problem *ProblemDetails, headers ...http.Header) error {
+	if problem == nil {
+		return fmt.Errorf("problem must not be nil")
+	}
+	if problem.Status == 0 {
+		problem.Status = http.StatusBadRequest
+	}
+	return t.writeJSONContent(w, problem.Status, "application/problem+json", problem, headers...)
+}
@@ -0,0 +1,99 @@
+package toolkit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTools_ErrorJSON_ProblemDetails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		baseURI        string
+		err            error
+		status         []int
+		expectedStatus int
+		expectedType   string
+		expectedTitle  string
+		expectedCode   string
+	}{
+		{name: "erro genérico", err: errors.New("algo deu errado"), expectedStatus: http.StatusBadRequest, expectedType: "about:blank", expectedTitle: "Bad Request"},
+		{name: "erro genérico com status", err: errors.New("sem permissão"), status: []int{http.StatusForbidden}, expectedStatus: http.StatusForbidden, expectedType: "about:blank", expectedTitle: "Forbidden"},
+		{name: "corpo muito grande", err: newRequestError(ErrBodyTooLarge, "body must not be larger than 10 bytes"), expectedStatus: http.StatusRequestEntityTooLarge, expectedType: "about:blank", expectedTitle: "Request Entity Too Large", expectedCode: "body-too-large"},
+		{name: "campo desconhecido com URI base", baseURI: "https://example.com/problems/", err: newRequestError(ErrUnknownField, `body contains unknown field "baz"`), expectedStatus: http.StatusBadRequest, expectedType: "https://example.com/problems/unknown-field", expectedTitle: "Unknown field", expectedCode: "unknown-field"},
+		{name: "falha de validação", err: ValidationErrors{{Field: "/email", Rule: "required", Message: "is required"}}, expectedStatus: http.StatusUnprocessableEntity, expectedType: "about:blank", expectedTitle: "Unprocessable Entity", expectedCode: "validation-failed"},
+		{name: "problema explícito", err: &ProblemDetails{Type: "https://example.com/out-of-credit", Title: "Sem crédito", Status: http.StatusPaymentRequired}, expectedStatus: http.StatusPaymentRequired, expectedType: "https://example.com/out-of-credit", expectedTitle: "Sem crédito"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTools := Tools{UseProblemDetails: true, ProblemTypeBaseURI: tc.baseURI}
+			rr := httptest.NewRecorder()
+
+			if err := testTools.ErrorJSON(rr, tc.err, tc.status...); err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("status incorreto: esperado %d, mas obteve %d", tc.expectedStatus, rr.Code)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type incorreto: esperado 'application/problem+json', mas obteve '%s'", ct)
+			}
+
+			var problem ProblemDetails
+			if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+				t.Fatalf("falha ao decodificar o problema: %v", err)
+			}
+			if problem.Type != tc.expectedType {
+				t.Errorf("type incorreto: esperado '%s', mas obteve '%s'", tc.expectedType, problem.Type)
+			}
+			if problem.Title != tc.expectedTitle {
+				t.Errorf("title incorreto: esperado '%s', mas obteve '%s'", tc.expectedTitle, problem.Title)
+			}
+			if problem.Status != tc.expectedStatus {
+				t.Errorf("membro status incorreto: esperado %d, mas obteve %d", tc.expectedStatus, problem.Status)
+			}
+			if code, _ := problem.Extensions["code"].(string); code != tc.expectedCode {
+				t.Errorf("code incorreto: esperado '%s', mas obteve '%s'", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestProblemDetails_MarshalJSON(t *testing.T) {
+	problem := ProblemDetails{
+		Title:      "Sem crédito",
+		Status:     http.StatusForbidden,
+		Instance:   "/account/12345",
+		Extensions: map[string]interface{}{"balance": 30, "title": "ignorado"},
+	}
+
+	out, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+
+	body := string(out)
+	for _, expected := range []string{`"balance":30`, `"title":"Sem crédito"`, `"instance":"/account/12345"`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("JSON deveria conter %s, obteve %s", expected, body)
+		}
+	}
+}
+
+func TestTools_ReadJSON_TypedErrors(t *testing.T) {
+	testTools := Tools{MaxJSONSize: 10}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"foo": "uma string muito longa"}`))
+
+	var data struct {
+		Foo string `json:"foo"`
+	}
+	err := testTools.ReadJSON(httptest.NewRecorder(), req, &data)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("esperado ErrBodyTooLarge, mas obteve %v", err)
+	}
+}
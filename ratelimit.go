@@ -0,0 +1,180 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited é retornado pelas chamadas remotas quando o RateLimiter está
+// no modo FailFast e não há fichas disponíveis para a chave.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimit é a taxa permitida para uma chave: Rate requisições por segundo,
+// com rajadas de até Burst requisições.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitStats são as métricas de uma chave do RateLimiter.
+type RateLimitStats struct {
+	// Requests é o total de requisições liberadas.
+	Requests int64
+	// Waited é o número de requisições que precisaram esperar.
+	Waited int64
+	// Rejected é o número de requisições recusadas no modo FailFast ou
+	// canceladas pelo contexto durante a espera.
+	Rejected int64
+	// TotalWait e MaxWait são a soma e o maior tempo de espera.
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// RateLimiter limita a taxa das chamadas remotas feitas pelo toolkit com um
+// balde de fichas por chave (por padrão, o host de destino). Por padrão, as
+// chamadas esperam, respeitando o contexto, até haver uma ficha disponível;
+// com FailFast, falham imediatamente com ErrRateLimited.
+type RateLimiter struct {
+	// Rate e Burst são o limite padrão de cada chave. Rate menor ou igual a
+	// zero não limita as chaves sem limite próprio.
+	Rate  float64
+	Burst int
+
+	// Limits define limites próprios para algumas chaves.
+	Limits map[string]RateLimit
+
+	// KeyFunc define a chave de uma requisição. Por padrão, o host da URL.
+	KeyFunc func(req *http.Request) string
+
+	// FailFast faz as chamadas sem ficha disponível falharem com
+	// ErrRateLimited em vez de esperar.
+	FailFast bool
+
+	// OnWait, se informado, é chamado após cada espera, para integração com
+	// sistemas de métricas.
+	OnWait func(key string, wait time.Duration)
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	stats   map[string]*RateLimitStats
+}
+
+// NewRateLimiter cria um RateLimiter que permite rate requisições por segundo
+// para cada host, com rajadas de até burst requisições.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{Rate: rate, Burst: burst}
+}
+
+// Wait aguarda uma ficha para key ou, com FailFast, falha se não houver uma
+// disponível. Retorna o tempo esperado.
+func (l *RateLimiter) Wait(ctx context.Context, key string) (time.Duration, error) {
+	bucket := l.bucket(key)
+	if bucket == nil {
+		l.record(key, 0, nil)
+		return 0, nil
+	}
+
+	if l.FailFast {
+		retryIn, ok := bucket.tryTake(1)
+		if !ok {
+			err := fmt.Errorf("%s: %w (retry in %v)", key, ErrRateLimited, retryIn.Round(time.Millisecond))
+			l.record(key, 0, err)
+			return 0, err
+		}
+		l.record(key, 0, nil)
+		return 0, nil
+	}
+
+	waited, err := bucket.wait(ctx, 1)
+	l.record(key, waited, err)
+	if waited > 0 && l.OnWait != nil {
+		l.OnWait(key, waited)
+	}
+	return waited, err
+}
+
+// Allow consome uma ficha de key, se houver, sem esperar.
+func (l *RateLimiter) Allow(key string) bool {
+	bucket := l.bucket(key)
+	if bucket == nil {
+		l.record(key, 0, nil)
+		return true
+	}
+	_, ok := bucket.tryTake(1)
+	if ok {
+		l.record(key, 0, nil)
+	} else {
+		l.record(key, 0, ErrRateLimited)
+	}
+	return ok
+}
+
+// Stats retorna as métricas de key.
+func (l *RateLimiter) Stats(key string) RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if s, ok := l.stats[key]; ok {
+		return *s
+	}
+	return RateLimitStats{}
+}
+
+// key retorna a chave de req.
+func (l *RateLimiter) key(req *http.Request) string {
+	if l.KeyFunc != nil {
+		return l.KeyFunc(req)
+	}
+	return req.URL.Host
+}
+
+// bucket retorna o balde de key, criando-o se necessário, ou nil se a chave
+// não tiver limite.
+func (l *RateLimiter) bucket(key string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		return b
+	}
+	limit, ok := l.Limits[key]
+	if !ok {
+		limit = RateLimit{Rate: l.Rate, Burst: l.Burst}
+	}
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+	b := newTokenBucket(limit.Rate, limit.Burst)
+	l.buckets[key] = b
+	return b
+}
+
+func (l *RateLimiter) record(key string, waited time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stats == nil {
+		l.stats = make(map[string]*RateLimitStats)
+	}
+	s, ok := l.stats[key]
+	if !ok {
+		s = &RateLimitStats{}
+		l.stats[key] = s
+	}
+	if err != nil {
+		s.Rejected++
+		return
+	}
+	s.Requests++
+	if waited > 0 {
+		s.Waited++
+		s.TotalWait += waited
+		s.MaxWait = max(s.MaxWait, waited)
+	}
+}
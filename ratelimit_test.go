@@ -0,0 +1,127 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTools_RateLimiter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, `{"error": false}`)
+	}))
+	defer server.Close()
+	host := urlHost(server.URL)
+
+	t.Run("bloqueante", func(t *testing.T) {
+		var waits atomic.Int32
+		limiter := NewRateLimiter(20, 1)
+		limiter.OnWait = func(key string, wait time.Duration) {
+			if key == host && wait > 0 {
+				waits.Add(1)
+			}
+		}
+		testTools := Tools{RateLimiter: limiter}
+
+		start := time.Now()
+		for range 3 {
+			if _, _, err := testTools.PushJSONToRemote(server.URL, nil); err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+			t.Errorf("as chamadas deveriam esperar pelas fichas: %v", elapsed)
+		}
+
+		stats := limiter.Stats(host)
+		if stats.Requests != 3 || stats.Waited != 2 || stats.TotalWait <= 0 || stats.MaxWait > stats.TotalWait || waits.Load() != 2 {
+			t.Errorf("métricas incorretas: %+v (OnWait %d)", stats, waits.Load())
+		}
+	})
+
+	t.Run("fail-fast", func(t *testing.T) {
+		calls.Store(0)
+		limiter := NewRateLimiter(1, 1)
+		limiter.FailFast = true
+		testTools := Tools{RateLimiter: limiter}
+
+		_, _, _ = testTools.PushJSONToRemote(server.URL, nil)
+		_, _, err := testTools.PushJSONToRemote(server.URL, nil)
+		if !errors.Is(err, ErrRateLimited) || calls.Load() != 1 {
+			t.Errorf("esperado ErrRateLimited sem chamar o servidor, mas obteve %v (%d chamadas)", err, calls.Load())
+		}
+		if stats := limiter.Stats(host); stats.Requests != 1 || stats.Rejected != 1 {
+			t.Errorf("métricas incorretas: %+v", stats)
+		}
+	})
+
+	t.Run("autenticação após a espera", func(t *testing.T) {
+		limiter := NewRateLimiter(10, 1)
+		var authenticated []time.Time
+		testTools := Tools{
+			RateLimiter: limiter,
+			RemoteAuthenticator: AuthenticatorFunc(func(req *http.Request) error {
+				authenticated = append(authenticated, time.Now())
+				return nil
+			}),
+		}
+
+		start := time.Now()
+		for range 2 {
+			if _, _, err := testTools.PushJSONToRemote(server.URL, nil); err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+		}
+		if len(authenticated) != 2 || authenticated[1].Sub(start) < 80*time.Millisecond {
+			t.Errorf("a autenticação deveria ocorrer após a espera pela ficha: %v", authenticated)
+		}
+	})
+
+	t.Run("cancelamento durante a espera", func(t *testing.T) {
+		limiter := NewRateLimiter(0.1, 1)
+		testTools := Tools{RateLimiter: limiter}
+		_, _, _ = testTools.PushJSONToRemote(server.URL, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, _, err := testTools.PushJSONToRemoteContext(ctx, server.URL, nil)
+		if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+			t.Errorf("esperado context.DeadlineExceeded, mas obteve %v", err)
+		}
+		if stats := limiter.Stats(host); stats.Rejected != 1 {
+			t.Errorf("métricas incorretas: %+v", stats)
+		}
+	})
+}
+
+func TestRateLimiter_Limits(t *testing.T) {
+	limiter := &RateLimiter{
+		Limits:  map[string]RateLimit{"parceiro": {Rate: 1, Burst: 2}},
+		KeyFunc: func(req *http.Request) string { return req.Header.Get("X-Partner") },
+	}
+
+	for i, expected := range []bool{true, true, false} {
+		if got := limiter.Allow("parceiro"); got != expected {
+			t.Errorf("chamada %d: esperado %v, mas obteve %v", i+1, expected, got)
+		}
+	}
+	for range 10 {
+		if !limiter.Allow("outro") {
+			t.Fatal("chaves sem limite não deveriam ser limitadas")
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set("X-Partner", "parceiro")
+	if key := limiter.key(req); key != "parceiro" {
+		t.Errorf("chave incorreta: %s", key)
+	}
+}
@@ -0,0 +1,235 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultRemoteTimeout é o timeout do cliente padrão quando RemoteTimeout não é informado.
+const defaultRemoteTimeout = 30 * time.Second
+
+// defaultMaxRemoteResponseSize limita o corpo das respostas remotas quando
+// MaxRemoteResponseSize não é informado.
+const defaultMaxRemoteResponseSize = 10 * 1024 * 1024
+
+// maxDrainSize é o máximo de bytes descartados de um corpo de resposta não lido
+// antes de fechá-lo. Corpos menores são lidos até o fim, permitindo a
+// reutilização da conexão; os maiores têm a conexão encerrada.
+const maxDrainSize = 64 * 1024
+
+// ErrRemoteResponseTooLarge é retornado (encapsulado) pelas chamadas remotas
+// quando o corpo da resposta excede MaxRemoteResponseSize.
+var ErrRemoteResponseTooLarge = errors.New("remote response too large")
+
+// PushJSONToRemoteContext envia dados em formato JSON para um endpoint remoto
+// via HTTP POST, como PushJSONToRemote, associando a chamada a ctx. Ao passar
+// o contexto da requisição de origem (r.Context()), a chamada é abortada quando
+// o cliente se desconecta ou o handler termina.
+//
+// Sem um cliente informado, é usado um cliente com o timeout RemoteTimeout
+// (30 segundos por padrão), que limita a duração de cada tentativa. Novas
+// tentativas seguem RetryPolicy; como POST não é idempotente, só são feitas
+// quando ctx tem uma chave definida com WithIdempotencyKey.
+//
+// Se RemoteAuthenticator for informado, ele adiciona as credenciais a cada
+// tentativa (token, assinatura HMAC ou OAuth2). RemoteLogging e RemoteTracing,
+// desabilitados por padrão, registram e rastreiam cada tentativa.
+//
+// O corpo da resposta é limitado a MaxRemoteResponseSize bytes (10 MB por
+// padrão); respostas maiores retornam um erro que encapsula
+// ErrRemoteResponseTooLarge.
+//
+// Com UseRemoteErrors habilitado, respostas fora da faixa 2xx retornam um
+// *RemoteError com o status, os cabeçalhos e o corpo da resposta.
+func (t *Tools) PushJSONToRemoteContext(ctx context.Context, uri string, data interface{}, client ...*http.Client) (*JSONResponse, int, error) {
+	// Converte os dados para JSON
+	jsonData, err := t.jsonEngine().Marshal(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Cria a requisição HTTP POST
+	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Envia a requisição
+	resp, err := t.doRemote(t.remoteClient(client...), req, t.RemoteAuthenticator)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer drainAndClose(resp.Body)
+
+	if t.UseRemoteErrors && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		body, truncated, err := readErrorBody(resp, defaultMaxErrorBodySize)
+		if err != nil {
+			return nil, resp.StatusCode, err
+		}
+		remoteErr := t.newRemoteError(resp, body, truncated)
+		return remoteErr.Response, resp.StatusCode, remoteErr
+	}
+
+	// Lê e retorna a resposta
+	var res JSONResponse
+	err = t.decodeRemoteResponse(resp, &res)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return &res, resp.StatusCode, nil
+}
+
+// remoteClient retorna o cliente informado ou um cliente com o timeout RemoteTimeout.
+func (t *Tools) remoteClient(client ...*http.Client) *http.Client {
+	if len(client) > 0 && client[0] != nil {
+		return client[0]
+	}
+
+	timeout := t.RemoteTimeout
+	if timeout <= 0 {
+		timeout = defaultRemoteTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// RemoteError é retornado pelas chamadas remotas, com UseRemoteErrors
+// habilitado, quando a resposta está fora da faixa 2xx.
+type RemoteError struct {
+	StatusCode int
+	Header     http.Header
+
+	// Body é o corpo da resposta, limitado a JSONClient.MaxErrorBodySize
+	// bytes (64 KB por padrão, e sempre 64 KB em PushJSONToRemote);
+	// Truncated indica se o corpo era maior que o limite.
+	Body      []byte
+	Truncated bool
+
+	// Response é o corpo decodificado, quando a resposta usa o formato
+	// JSONResponse do toolkit.
+	Response *JSONResponse
+
+	// Problem é o corpo decodificado, quando a resposta é application/problem+json.
+	Problem *ProblemDetails
+}
+
+func (e *RemoteError) Error() string {
+	msg := fmt.Sprintf("remote responded with status %d", e.StatusCode)
+	switch {
+	case e.Problem != nil && e.Problem.Error() != "":
+		return msg + ": " + e.Problem.Error()
+	case e.Response != nil && e.Response.Message != "":
+		return msg + ": " + e.Response.Message
+	case len(e.Body) > 0:
+		body := strings.ToValidUTF8(string(e.Body), "")
+		if len(body) > 200 {
+			body = body[:200] + "..."
+		}
+		return msg + ": " + strings.TrimSpace(body)
+	}
+	return msg
+}
+
+// readErrorBody lê até limit bytes do corpo de uma resposta de erro e indica
+// se o corpo foi truncado.
+func readErrorBody(resp *http.Response, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		limit = defaultMaxErrorBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if int64(len(body)) > limit {
+		return body[:limit], true, err
+	}
+	return body, false, err
+}
+
+// newRemoteError monta um *RemoteError a partir de uma resposta fora da faixa
+// 2xx, decodificando o corpo como problem+json ou JSONResponse quando possível.
+func (t *Tools) newRemoteError(resp *http.Response, body []byte, truncated bool) *RemoteError {
+	e := &RemoteError{StatusCode: resp.StatusCode, Header: resp.Header, Body: body, Truncated: truncated}
+	if truncated || len(body) == 0 {
+		return e
+	}
+
+	opts := JSONDecodeOptions{AllowUnknownFields: true}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/problem+json":
+		var p ProblemDetails
+		if err := t.jsonEngine().Decode(bytes.NewReader(body), &p, opts); err == nil {
+			e.Problem = &p
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var res JSONResponse
+		err := t.jsonEngine().Decode(bytes.NewReader(body), &res, opts)
+		if err == nil && (res.Error || res.Message != "") {
+			e.Response = &res
+		}
+	}
+	return e
+}
+
+// maxRemoteResponseBytes retorna o limite do corpo das respostas remotas.
+func (t *Tools) maxRemoteResponseBytes() int64 {
+	if t.MaxRemoteResponseSize > 0 {
+		return int64(t.MaxRemoteResponseSize)
+	}
+	return defaultMaxRemoteResponseSize
+}
+
+// decodeRemoteResponse decodifica o corpo de resp em v, limitado a
+// MaxRemoteResponseSize bytes.
+func (t *Tools) decodeRemoteResponse(resp *http.Response, v interface{}) error {
+	limit := t.maxRemoteResponseBytes()
+	tooLarge := fmt.Errorf("remote response must not be larger than %d bytes: %w", limit, ErrRemoteResponseTooLarge)
+	// Com Content-Length conhecido, a resposta é recusada sem ser lida.
+	if resp.ContentLength > limit {
+		return tooLarge
+	}
+
+	body := &limitedBody{r: resp.Body, remaining: limit}
+	err := t.jsonEngine().Decode(body, v, JSONDecodeOptions{AllowUnknownFields: true})
+	if body.exceeded {
+		return tooLarge
+	}
+	return err
+}
+
+// limitedBody lê até remaining bytes e falha, marcando exceeded, se o corpo
+// for maior.
+type limitedBody struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, ErrRemoteResponseTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.r.Read(p)
+	if int64(n) > b.remaining {
+		b.exceeded = true
+		return int(b.remaining), ErrRemoteResponseTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// drainAndClose descarta até maxDrainSize bytes de body e o fecha, para que a
+// conexão possa ser reutilizada pelo cliente.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainSize))
+	body.Close()
+}
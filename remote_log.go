@@ -0,0 +1,225 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxLogBodySize limita os corpos registrados quando
+// RemoteLogging.MaxBodySize não é informado.
+const defaultMaxLogBodySize = 4 * 1024
+
+// redactedValue substitui cabeçalhos e campos omitidos dos logs.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders são sempre omitidos dos logs.
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RemoteLogging configura o log estruturado das chamadas remotas feitas pelo
+// toolkit. Cada tentativa gera um registro "remote request" com o método, a
+// URL, o status, a duração e os tamanhos da requisição e da resposta. O
+// registro é emitido quando o corpo da resposta é fechado, de modo que a
+// duração e o tamanho da resposta incluem a leitura do corpo.
+type RemoteLogging struct {
+	// Logger recebe os registros. Se nil, é usado slog.Default().
+	Logger *slog.Logger
+
+	// Level é o nível dos registros. Chamadas que falham sem resposta são
+	// registradas em slog.LevelError.
+	Level slog.Level
+
+	// Headers inclui os cabeçalhos da requisição e da resposta.
+	Headers bool
+
+	// Bodies inclui os corpos da requisição e da resposta. Corpos maiores
+	// que MaxBodySize (4 KB por padrão) são omitidos.
+	Bodies      bool
+	MaxBodySize int
+
+	// RedactHeaders são cabeçalhos omitidos dos logs, além de Authorization,
+	// Proxy-Authorization, Cookie e Set-Cookie.
+	RedactHeaders []string
+
+	// RedactFields são campos JSON omitidos dos corpos, em qualquer nível. A
+	// comparação não diferencia maiúsculas de minúsculas. Corpos que não são
+	// JSON válido são omitidos quando RedactFields é informado.
+	RedactFields []string
+}
+
+// remoteLogEntry acompanha o registro de uma tentativa.
+type remoteLogEntry struct {
+	cfg     *RemoteLogging
+	req     *http.Request
+	attempt int
+	start   time.Time
+	reqBody []byte
+	reqSize int64
+}
+
+// startRemoteLog inicia o registro de uma tentativa, ou retorna nil se
+// RemoteLogging não estiver configurado.
+func (t *Tools) startRemoteLog(req *http.Request, attempt int) *remoteLogEntry {
+	if t.RemoteLogging == nil {
+		return nil
+	}
+	e := &remoteLogEntry{cfg: t.RemoteLogging, req: req, attempt: attempt, start: time.Now(), reqSize: req.ContentLength}
+	if e.cfg.Bodies && req.GetBody != nil && req.ContentLength <= int64(e.cfg.maxBodySize()) {
+		if rc, err := req.GetBody(); err == nil {
+			e.reqBody, _ = io.ReadAll(io.LimitReader(rc, int64(e.cfg.maxBodySize())+1))
+			rc.Close()
+		}
+	}
+	return e
+}
+
+// finish registra a tentativa. Com uma resposta, o registro é adiado até o
+// fechamento do corpo, que passa a ser contado (e capturado, com Bodies).
+func (e *remoteLogEntry) finish(resp *http.Response, err error) {
+	if err != nil || resp == nil {
+		e.log(nil, nil, 0, err)
+		return
+	}
+	body := &loggedBody{rc: resp.Body, capture: e.cfg.Bodies, limit: e.cfg.maxBodySize()}
+	body.onClose = func() {
+		e.log(resp, body.buf.Bytes(), body.n, nil)
+	}
+	resp.Body = body
+}
+
+func (e *remoteLogEntry) log(resp *http.Response, respBody []byte, respSize int64, err error) {
+	logger := e.cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", e.req.Method),
+		slog.String("url", e.req.URL.Redacted()),
+		slog.Int("attempt", e.attempt),
+		slog.Duration("duration", time.Since(e.start)),
+		slog.Int64("request_size", max(e.reqSize, 0)),
+	}
+	if e.cfg.Headers {
+		attrs = append(attrs, slog.Any("request_headers", e.cfg.redactHeaders(e.req.Header)))
+	}
+	if e.cfg.Bodies && e.reqBody != nil {
+		attrs = append(attrs, slog.String("request_body", e.cfg.redactBody(e.reqBody)))
+	}
+
+	level := e.cfg.Level
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level = slog.LevelError
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode), slog.Int64("response_size", respSize))
+		if e.cfg.Headers {
+			attrs = append(attrs, slog.Any("response_headers", e.cfg.redactHeaders(resp.Header)))
+		}
+		if e.cfg.Bodies && respBody != nil {
+			attrs = append(attrs, slog.String("response_body", e.cfg.redactBody(respBody)))
+		}
+	}
+	logger.LogAttrs(e.req.Context(), level, "remote request", attrs...)
+}
+
+func (l *RemoteLogging) maxBodySize() int {
+	if l.MaxBodySize > 0 {
+		return l.MaxBodySize
+	}
+	return defaultMaxLogBodySize
+}
+
+// redactHeaders retorna uma cópia de header com os cabeçalhos sensíveis omitidos.
+func (l *RemoteLogging) redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		out[key] = strings.Join(values, ", ")
+	}
+	for _, key := range append(defaultRedactedHeaders, l.RedactHeaders...) {
+		if _, ok := header[http.CanonicalHeaderKey(key)]; ok {
+			out[http.CanonicalHeaderKey(key)] = redactedValue
+		}
+	}
+	return out
+}
+
+// redactBody retorna body como texto, com os campos de RedactFields omitidos.
+func (l *RemoteLogging) redactBody(body []byte) string {
+	if len(body) > l.maxBodySize() {
+		return "[OMITTED]"
+	}
+	if len(l.RedactFields) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return string(body)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "[OMITTED]"
+	}
+	out, err := json.Marshal(l.redactValue(v))
+	if err != nil {
+		return "[OMITTED]"
+	}
+	return string(out)
+}
+
+func (l *RemoteLogging) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if l.redactedField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = l.redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = l.redactValue(value)
+		}
+	}
+	return v
+}
+
+func (l *RemoteLogging) redactedField(key string) bool {
+	for _, field := range l.RedactFields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// loggedBody conta (e, com capture, guarda até limit+1) os bytes lidos do corpo
+// e chama onClose uma única vez ao ser fechado.
+type loggedBody struct {
+	rc      io.ReadCloser
+	capture bool
+	limit   int
+	buf     bytes.Buffer
+	n       int64
+	once    sync.Once
+	onClose func()
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	b.n += int64(n)
+	if b.capture && b.buf.Len() <= b.limit {
+		b.buf.Write(p[:min(n, b.limit+1-b.buf.Len())])
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.rc.Close()
+	b.once.Do(b.onClose)
+	return err
+}
@@ -0,0 +1,140 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logRecords decodifica os registros de um slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("registro inválido %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestTools_RemoteLogging(t *testing.T) {
+	const respBody = `{"error": false, "message": "ok", "data": {"token": "segredo"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "sessao=123")
+		w.Header().Set("X-Request-ID", "abc")
+		_, _ = io.WriteString(w, respBody)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	testTools := Tools{
+		RemoteAuthenticator: BearerToken("segredo"),
+		RemoteLogging: &RemoteLogging{
+			Logger:        slog.New(slog.NewJSONHandler(&buf, nil)),
+			Headers:       true,
+			Bodies:        true,
+			RedactHeaders: []string{"x-api-key"},
+			RedactFields:  []string{"password", "TOKEN"},
+		},
+	}
+
+	payload := map[string]interface{}{"user": "ana", "password": "123", "items": []map[string]string{{"password": "456"}}}
+	_, _, err := testTools.PushJSONToRemoteContext(WithIdempotencyKey(t.Context(), "chave"), server.URL+"?q=1", payload, &http.Client{Transport: headerTransport{"X-Api-Key": "k"}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("esperado 1 registro, mas obteve %d", len(records))
+	}
+	r := records[0]
+	if r["msg"] != "remote request" || r["level"] != "INFO" || r["method"] != "POST" || r["url"] != server.URL+"?q=1" || r["status"] != float64(200) || r["attempt"] != float64(1) {
+		t.Errorf("registro incorreto: %v", r)
+	}
+	reqBody, _ := json.Marshal(payload)
+	if r["request_size"] != float64(len(reqBody)) || r["response_size"] != float64(len(respBody)) {
+		t.Errorf("tamanhos incorretos: %v %v", r["request_size"], r["response_size"])
+	}
+
+	reqHeaders, _ := r["request_headers"].(map[string]interface{})
+	respHeaders, _ := r["response_headers"].(map[string]interface{})
+	if reqHeaders["Authorization"] != redactedValue || reqHeaders["X-Api-Key"] != redactedValue || reqHeaders["Idempotency-Key"] != "chave" {
+		t.Errorf("cabeçalhos da requisição incorretos: %v", reqHeaders)
+	}
+	if respHeaders["Set-Cookie"] != redactedValue || respHeaders["X-Request-Id"] != "abc" {
+		t.Errorf("cabeçalhos da resposta incorretos: %v", respHeaders)
+	}
+
+	if body := r["request_body"].(string); strings.Contains(body, "123") || strings.Contains(body, "456") || !strings.Contains(body, "ana") {
+		t.Errorf("corpo da requisição não foi mascarado: %s", body)
+	}
+	if body := r["response_body"].(string); strings.Contains(body, "segredo") || !strings.Contains(body, "ok") {
+		t.Errorf("corpo da resposta não foi mascarado: %s", body)
+	}
+}
+
+func TestTools_RemoteLogging_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	testTools := Tools{RemoteLogging: &RemoteLogging{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	if _, _, err := testTools.PushJSONToRemote(url, nil); err == nil {
+		t.Fatal("esperado erro de conexão")
+	}
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["level"] != "ERROR" || records[0]["error"] == nil || records[0]["status"] != nil {
+		t.Errorf("registro de erro incorreto: %v", records)
+	}
+}
+
+func TestRemoteLogging_redactBody(t *testing.T) {
+	l := &RemoteLogging{MaxBodySize: 20, RedactFields: []string{"senha"}}
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "campo mascarado", body: `{"senha": "x"}`, expected: `{"senha":"[REDACTED]"}`},
+		{name: "corpo grande demais", body: `{"a": "` + strings.Repeat("b", 30) + `"}`, expected: "[OMITTED]"},
+		{name: "JSON inválido", body: `senha=x`, expected: "[OMITTED]"},
+		{name: "corpo vazio", body: "", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := l.redactBody([]byte(tc.body)); got != tc.expected {
+				t.Errorf("esperado '%s', mas obteve '%s'", tc.expected, got)
+			}
+		})
+	}
+
+	if got := (&RemoteLogging{}).redactBody([]byte("texto")); got != "texto" {
+		t.Errorf("sem RedactFields o corpo deveria ser mantido: %s", got)
+	}
+}
+
+// headerTransport acrescenta cabeçalhos fixos às requisições.
+type headerTransport map[string]string
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	for key, value := range h {
+		r.Header.Set(key, value)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -0,0 +1,211 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTools_PushJSONToRemoteContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	t.Run("cancelamento do contexto", func(t *testing.T) {
+		var testTools Tools
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, _, err := testTools.PushJSONToRemoteContext(ctx, server.URL, map[string]string{"foo": "bar"})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("esperado context.Canceled, mas obteve %v", err)
+		}
+		if time.Since(start) > 2*time.Second {
+			t.Error("a chamada deveria ser abortada logo após o cancelamento")
+		}
+	})
+
+	t.Run("timeout do cliente padrão", func(t *testing.T) {
+		testTools := Tools{RemoteTimeout: 50 * time.Millisecond}
+
+		_, _, err := testTools.PushJSONToRemote(server.URL, map[string]string{"foo": "bar"})
+		var timeoutErr interface{ Timeout() bool }
+		if !errors.As(err, &timeoutErr) || !timeoutErr.Timeout() {
+			t.Fatalf("esperado erro de timeout, mas obteve %v", err)
+		}
+	})
+}
+
+func TestTools_remoteClient(t *testing.T) {
+	var testTools Tools
+	if timeout := testTools.remoteClient().Timeout; timeout != defaultRemoteTimeout {
+		t.Errorf("timeout padrão incorreto: %v", timeout)
+	}
+
+	custom := &http.Client{}
+	if testTools.remoteClient(custom) != custom {
+		t.Error("o cliente informado deveria ser usado")
+	}
+}
+
+func TestTools_UseRemoteErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		contentType     string
+		status          int
+		body            string
+		expectedMessage string
+		expectProblem   bool
+		expectResponse  bool
+		expectTruncated bool
+	}{
+		{name: "problem+json", contentType: "application/problem+json", status: http.StatusConflict, body: `{"title": "Conflict", "detail": "pedido duplicado", "code": "duplicate"}`, expectedMessage: "remote responded with status 409: pedido duplicado", expectProblem: true},
+		{name: "JSONResponse", contentType: "application/json", status: http.StatusInternalServerError, body: `{"error": true, "message": "falha interna"}`, expectedMessage: "remote responded with status 500: falha interna", expectResponse: true},
+		{name: "HTML", contentType: "text/html", status: http.StatusBadGateway, body: "<h1>Bad Gateway</h1>", expectedMessage: "remote responded with status 502: <h1>Bad Gateway</h1>"},
+		{name: "corpo vazio", status: http.StatusServiceUnavailable, expectedMessage: "remote responded with status 503"},
+		{name: "corpo truncado", contentType: "application/json", status: http.StatusInternalServerError, body: `{"message": "` + strings.Repeat("a", 70*1024) + `"}`, expectTruncated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set("Content-Type", tc.contentType)
+				}
+				w.Header().Set("X-Request-ID", "abc")
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			}))
+			defer server.Close()
+
+			testTools := Tools{UseRemoteErrors: true}
+			_, status, err := testTools.PushJSONToRemote(server.URL, nil)
+
+			var remoteErr *RemoteError
+			if !errors.As(err, &remoteErr) {
+				t.Fatalf("esperado *RemoteError, mas obteve %v", err)
+			}
+			if status != tc.status || remoteErr.StatusCode != tc.status || remoteErr.Header.Get("X-Request-ID") != "abc" {
+				t.Errorf("status ou cabeçalhos incorretos: %d %+v", status, remoteErr)
+			}
+			if tc.expectedMessage != "" && err.Error() != tc.expectedMessage {
+				t.Errorf("mensagem incorreta: esperado '%s', mas obteve '%s'", tc.expectedMessage, err.Error())
+			}
+			if (remoteErr.Problem != nil) != tc.expectProblem || (remoteErr.Response != nil) != tc.expectResponse {
+				t.Errorf("corpo decodificado incorreto: problem %+v, response %+v", remoteErr.Problem, remoteErr.Response)
+			}
+			if tc.expectProblem && remoteErr.Problem.Extensions["code"] != "duplicate" {
+				t.Errorf("extensões do problema incorretas: %+v", remoteErr.Problem.Extensions)
+			}
+			if remoteErr.Truncated != tc.expectTruncated || len(remoteErr.Body) > defaultMaxErrorBodySize {
+				t.Errorf("truncamento incorreto: %v, %d bytes", remoteErr.Truncated, len(remoteErr.Body))
+			}
+		})
+	}
+}
+
+func TestTools_UseRemoteErrors_Disabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, `{"error": true, "message": "falha interna"}`)
+	}))
+	defer server.Close()
+
+	var testTools Tools
+	res, status, err := testTools.PushJSONToRemote(server.URL, nil)
+	if err != nil || status != http.StatusInternalServerError || res.Message != "falha interna" {
+		t.Errorf("sem UseRemoteErrors, a resposta deveria ser decodificada: %v %d %+v", err, status, res)
+	}
+}
+
+func TestTools_MaxRemoteResponseSize(t *testing.T) {
+	large := `{"message": "` + strings.Repeat("a", 200) + `"}`
+	testCases := []struct {
+		name        string
+		chunked     bool
+		body        string
+		expectError bool
+	}{
+		{name: "dentro do limite", body: `{"message": "ok"}`},
+		{name: "Content-Length acima do limite", body: large, expectError: true},
+		{name: "corpo sem Content-Length acima do limite", body: large, chunked: true, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.chunked {
+					w.(http.Flusher).Flush()
+				}
+				_, _ = io.WriteString(w, tc.body)
+			}))
+			defer server.Close()
+
+			testTools := Tools{MaxRemoteResponseSize: 100}
+			res, _, err := testTools.PushJSONToRemote(server.URL, nil)
+			if tc.expectError {
+				if !errors.Is(err, ErrRemoteResponseTooLarge) || err.Error() != "remote response must not be larger than 100 bytes: remote response too large" {
+					t.Errorf("esperado ErrRemoteResponseTooLarge, mas obteve %v", err)
+				}
+				return
+			}
+			if err != nil || res.Message != "ok" {
+				t.Errorf("erro inesperado: %v", err)
+			}
+		})
+	}
+
+	t.Run("JSONClient", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, large)
+		}))
+		defer server.Close()
+
+		testTools := Tools{MaxRemoteResponseSize: 100}
+		_, err := GetJSON[JSONResponse](context.Background(), testTools.NewJSONClient(server.URL), "/")
+		if !errors.Is(err, ErrRemoteResponseTooLarge) {
+			t.Errorf("esperado ErrRemoteResponseTooLarge, mas obteve %v", err)
+		}
+	})
+}
+
+func TestTools_PushJSONToRemote_ReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		// O tipo incorreto interrompe a decodificação antes do fim do corpo.
+		_, _ = io.WriteString(w, `{"message": 1}`+strings.Repeat(" ", 32*1024))
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	var testTools Tools
+	client := server.Client()
+	for range 3 {
+		if _, _, err := testTools.PushJSONToRemote(server.URL, nil, client); err == nil {
+			t.Fatal("esperado erro de decodificação")
+		}
+	}
+	if connections.Load() != 1 {
+		t.Errorf("a conexão deveria ser reutilizada, mas foram abertas %d", connections.Load())
+	}
+}
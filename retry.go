@@ -0,0 +1,215 @@
+package toolkit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configura novas tentativas das chamadas remotas feitas pelo
+// toolkit, como PushJSONToRemote. São repetidas as chamadas que falham por
+// erro de rede ou recebem 429 ou 5xx, com espera exponencial e jitter entre
+// as tentativas. O cabeçalho Retry-After da resposta, quando presente,
+// substitui a espera calculada.
+//
+// Somente métodos idempotentes (GET, HEAD, OPTIONS, TRACE, PUT e DELETE) são
+// repetidos, a menos que o contexto tenha uma chave de idempotência definida
+// com WithIdempotencyKey.
+type RetryPolicy struct {
+	// MaxAttempts é o total de tentativas, incluindo a primeira.
+	MaxAttempts int
+
+	// BaseDelay é a espera antes da segunda tentativa, dobrada a cada nova
+	// tentativa (100ms por padrão).
+	BaseDelay time.Duration
+
+	// MaxDelay limita a espera entre tentativas (5s por padrão). Se o servidor
+	// pedir, via Retry-After, uma espera maior, a resposta é retornada sem
+	// novas tentativas.
+	MaxDelay time.Duration
+
+	// RetryOn, se informado, substitui o critério padrão para repetir uma
+	// chamada. resp é nil quando err não é nil.
+	RetryOn func(resp *http.Response, err error) bool
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey retorna um contexto que envia key no cabeçalho
+// Idempotency-Key das chamadas remotas e permite repetir métodos não
+// idempotentes, como POST.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// doRemote envia req com client aplicando RetryPolicy, RateLimiter,
+// CircuitBreaker, RemoteTracing e RemoteLogging. O corpo de req deve poder ser
+// recriado (GetBody) para que a chamada seja repetida. Se auth não for nil, ele
+// é aplicado a cada tentativa, logo antes do envio.
+func (t *Tools) doRemote(client *http.Client, req *http.Request, auth Authenticator) (*http.Response, error) {
+	ctx := req.Context()
+	key := idempotencyKey(ctx)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	policy := t.RetryPolicy
+	attempts := 1
+	if policy != nil && (isIdempotentMethod(req.Method) || key != "") && (req.Body == nil || req.GetBody != nil) {
+		attempts = max(policy.MaxAttempts, 1)
+	}
+	// Sem trace no contexto, todas as tentativas pertencem a um mesmo novo trace.
+	var traceID string
+	if t.RemoteTracing != nil && t.RemoteTracing.PropagateTraceParent && req.Header.Get("traceparent") == "" {
+		traceID = randomHex(16)
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		if t.RateLimiter != nil {
+			if _, err := t.RateLimiter.Wait(ctx, t.RateLimiter.key(attemptReq)); err != nil {
+				return nil, err
+			}
+		}
+
+		var done func(*http.Response, error)
+		if t.CircuitBreaker != nil {
+			var err error
+			done, err = t.CircuitBreaker.allow(req.URL.Host)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", req.URL.Host, err)
+			}
+		}
+
+		// As credenciais são aplicadas após as esperas, logo antes do envio,
+		// para que assinaturas com horário e tokens não expirem na fila.
+		if auth != nil {
+			if err := auth.Authenticate(attemptReq); err != nil {
+				if done != nil {
+					// A chamada não foi feita e não conta como falha do host.
+					done(nil, context.Canceled)
+				}
+				return nil, err
+			}
+		}
+
+		attemptReq, end := t.startRemoteSpan(attemptReq, traceID)
+		entry := t.startRemoteLog(attemptReq, attempt)
+
+		resp, err := client.Do(attemptReq)
+		if done != nil {
+			done(resp, err)
+		}
+		if end != nil {
+			end(resp, err)
+		}
+		if entry != nil {
+			entry.finish(resp, err)
+		}
+		// Um 401 indica credenciais recusadas; tokens em cache são descartados.
+		if inv, ok := auth.(interface{ Invalidate() }); ok && err == nil && resp.StatusCode == http.StatusUnauthorized {
+			inv.Invalidate()
+		}
+		if attempt >= attempts || ctx.Err() != nil || !policy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay, ok := policy.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if p.RetryOn != nil {
+		return p.RetryOn(resp, err)
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// delay retorna a espera antes da próxima tentativa. Retorna false quando o
+// servidor pede, via Retry-After, uma espera maior que MaxDelay.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Second
+	}
+
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d, d <= maxDelay
+		}
+	}
+
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, maxDelay)
+	// Jitter: espera entre metade e o total do valor calculado.
+	half := d / 2
+	return half + rand.N(half+1), true
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After em segundos ou como data HTTP.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// isIdempotentMethod indica se o método é idempotente conforme a RFC 9110.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
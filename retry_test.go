@@ -0,0 +1,128 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTools_RetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name             string
+		idempotencyKey   string
+		statuses         []int
+		retryAfter       string
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{name: "repete após 503", idempotencyKey: "pedido-1", statuses: []int{503, 503, 201}, expectedAttempts: 3, expectedStatus: http.StatusCreated},
+		{name: "repete após 429 com Retry-After", idempotencyKey: "pedido-2", statuses: []int{429, 201}, retryAfter: "0", expectedAttempts: 2, expectedStatus: http.StatusCreated},
+		{name: "limite de tentativas", idempotencyKey: "pedido-3", statuses: []int{500, 500, 500, 500}, expectedAttempts: 3, expectedStatus: http.StatusInternalServerError},
+		{name: "não repete erros do cliente", idempotencyKey: "pedido-4", statuses: []int{400, 201}, expectedAttempts: 1, expectedStatus: http.StatusBadRequest},
+		{name: "não repete POST sem chave de idempotência", statuses: []int{503, 201}, expectedAttempts: 1, expectedStatus: http.StatusServiceUnavailable},
+		{name: "Retry-After maior que o limite", idempotencyKey: "pedido-5", statuses: []int{503, 201}, retryAfter: "3600", expectedAttempts: 1, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"foo":"bar"}` {
+					t.Errorf("corpo incorreto na tentativa %d: %q", n, body)
+				}
+				if r.Header.Get("Idempotency-Key") != tc.idempotencyKey {
+					t.Errorf("Idempotency-Key incorreto: %q", r.Header.Get("Idempotency-Key"))
+				}
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.statuses[n-1])
+				_, _ = io.WriteString(w, `{"error": false}`)
+			}))
+			defer server.Close()
+
+			testTools := Tools{RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}}
+			ctx := context.Background()
+			if tc.idempotencyKey != "" {
+				ctx = WithIdempotencyKey(ctx, tc.idempotencyKey)
+			}
+
+			_, status, err := testTools.PushJSONToRemoteContext(ctx, server.URL, map[string]string{"foo": "bar"})
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if status != tc.expectedStatus {
+				t.Errorf("status incorreto: esperado %d, mas obteve %d", tc.expectedStatus, status)
+			}
+			if attempts.Load() != tc.expectedAttempts {
+				t.Errorf("quantidade de tentativas incorreta: esperado %d, mas obteve %d", tc.expectedAttempts, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestTools_RetryPolicy_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	uri := server.URL
+	server.Close()
+
+	var retries atomic.Int32
+	testTools := Tools{RetryPolicy: &RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		RetryOn: func(resp *http.Response, err error) bool {
+			retries.Add(1)
+			return err != nil
+		},
+	}}
+
+	ctx := WithIdempotencyKey(context.Background(), "pedido")
+	if _, _, err := testTools.PushJSONToRemoteContext(ctx, uri, nil); err == nil {
+		t.Fatal("esperado erro de conexão")
+	}
+	if retries.Load() != 1 {
+		t.Errorf("esperado uma nova tentativa, mas obteve %d", retries.Load())
+	}
+}
+
+func TestTools_RetryPolicy_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testTools := Tools{RetryPolicy: &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}}
+	ctx, cancel := context.WithTimeout(WithIdempotencyKey(context.Background(), "pedido"), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := testTools.PushJSONToRemoteContext(ctx, server.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("esperado context.DeadlineExceeded durante a espera, mas obteve %v", err)
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second, 100: time.Second} {
+		d, ok := policy.delay(attempt, nil)
+		if !ok || d < expected/2 || d > expected {
+			t.Errorf("espera incorreta na tentativa %d: esperado entre %v e %v, mas obteve %v", attempt, expected/2, expected, d)
+		}
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if d, ok := parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now); !ok || d != 30*time.Second {
+		t.Errorf("Retry-After com data interpretado incorretamente: %v", d)
+	}
+	if _, ok := parseRetryAfter("amanhã", now); ok {
+		t.Error("Retry-After inválido deveria ser ignorado")
+	}
+}
@@ -0,0 +1,196 @@
+package toolkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSSEClosed é retornado ao enviar eventos por um SSEWriter já fechado.
+var ErrSSEClosed = errors.New("sse: writer closed")
+
+// SSEEvent representa um evento Server-Sent Events. Data do tipo string ou
+// []byte é enviado como está; qualquer outro valor é codificado em JSON.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  interface{}
+	Retry time.Duration
+}
+
+// SSEWriter envia eventos text/event-stream para o cliente. É seguro para uso
+// concorrente e deixa de enviar eventos quando o cliente se desconecta.
+type SSEWriter struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	ctx         context.Context
+	lastEventID string
+
+	mu     sync.Mutex
+	closed bool
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSSEWriter prepara a resposta para Server-Sent Events, enviando os
+// cabeçalhos imediatamente. Se SSEHeartbeat for maior que zero, um comentário
+// é enviado nesse intervalo para manter a conexão aberta através de proxies.
+// Retorna erro se o response writer não suportar flush.
+func (t *Tools) NewSSEWriter(w http.ResponseWriter, r *http.Request) (*SSEWriter, error) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+
+	s := &SSEWriter{
+		w:           w,
+		rc:          http.NewResponseController(w),
+		ctx:         r.Context(),
+		lastEventID: r.Header.Get("Last-Event-ID"),
+		stop:        make(chan struct{}),
+	}
+	if err := s.rc.Flush(); err != nil {
+		return nil, fmt.Errorf("sse: streaming not supported: %w", err)
+	}
+
+	if t.SSEHeartbeat > 0 {
+		s.wg.Add(1)
+		go s.heartbeat(t.SSEHeartbeat)
+	}
+
+	return s, nil
+}
+
+// LastEventID retorna o cabeçalho Last-Event-ID enviado pelo navegador ao
+// reconectar, permitindo retomar o stream a partir do último evento recebido.
+func (s *SSEWriter) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done é fechado quando o cliente se desconecta.
+func (s *SSEWriter) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Send envia um evento e faz o flush da resposta.
+func (s *SSEWriter) Send(ev SSEEvent) error {
+	if strings.ContainsAny(ev.ID, "\r\n\x00") || strings.ContainsAny(ev.Event, "\r\n\x00") {
+		return errors.New("sse: event id and name must not contain line breaks or NUL")
+	}
+
+	var data string
+	switch d := ev.Data.(type) {
+	case nil:
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		out, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		data = string(out)
+	}
+
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", ev.ID)
+	}
+	if ev.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", ev.Event)
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	for _, line := range sseLines(data) {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// SendJSON envia um evento com o nome informado e data codificado em JSON.
+func (s *SSEWriter) SendJSON(event string, data interface{}) error {
+	out, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.Send(SSEEvent{Event: event, Data: out})
+}
+
+// Comment envia um comentário, ignorado pelo navegador.
+func (s *SSEWriter) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range sseLines(text) {
+		fmt.Fprintf(&b, ": %s\n", line)
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// sseLines divide s nas quebras de linha reconhecidas pelo navegador (\r\n,
+// \r e \n), impedindo que o conteúdo injete campos no evento.
+func sseLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.Split(s, "\n")
+}
+
+// Close encerra o heartbeat e impede novos envios. Não fecha a conexão, que
+// termina quando o handler retorna.
+func (s *SSEWriter) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.stop)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *SSEWriter) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSSEClosed
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := s.w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *SSEWriter) heartbeat(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Comment("heartbeat"); err != nil {
+				return
+			}
+		case <-s.stop:
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package toolkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTools_NewSSEWriter(t *testing.T) {
+	testTools := Tools{SSEHeartbeat: 10 * time.Millisecond}
+
+	var lastEventID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sse, err := testTools.NewSSEWriter(w, r)
+		if err != nil {
+			t.Errorf("erro inesperado recebido: %v", err)
+			return
+		}
+		defer sse.Close()
+
+		lastEventID = sse.LastEventID()
+
+		_ = sse.Send(SSEEvent{ID: "42", Event: "progress", Data: map[string]int{"percent": 50}, Retry: 3 * time.Second})
+		_ = sse.Send(SSEEvent{Data: "linha 1\nlinha 2"})
+		time.Sleep(50 * time.Millisecond)
+		_ = sse.SendJSON("done", struct {
+			OK bool `json:"ok"`
+		}{OK: true})
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Last-Event-ID", "41")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type incorreto: obteve '%s'", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	stream := string(body)
+
+	expected := []string{
+		"id: 42\nevent: progress\nretry: 3000\ndata: {\"percent\":50}\n\n",
+		"data: linha 1\ndata: linha 2\n\n",
+		": heartbeat\n\n",
+		"event: done\ndata: {\"ok\":true}\n\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(stream, e) {
+			t.Errorf("o stream deveria conter %q, obteve %q", e, stream)
+		}
+	}
+	if lastEventID != "41" {
+		t.Errorf("Last-Event-ID incorreto: esperado '41', mas obteve '%s'", lastEventID)
+	}
+}
+
+func TestSSEWriter_ClientDisconnect(t *testing.T) {
+	var testTools Tools
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/events", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	sse, err := testTools.NewSSEWriter(rr, req)
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+
+	cancel()
+	select {
+	case <-sse.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done deveria ser fechado após a desconexão do cliente")
+	}
+
+	if err := sse.Send(SSEEvent{Data: "tarde demais"}); err == nil {
+		t.Error("um erro era esperado após a desconexão, mas nenhum foi recebido")
+	}
+
+	sse.Close()
+	if err := sse.Comment("fechado"); err != ErrSSEClosed {
+		t.Errorf("esperado ErrSSEClosed, mas obteve %v", err)
+	}
+}
+
+func TestSSEWriter_LineBreaks(t *testing.T) {
+	var testTools Tools
+	rr := httptest.NewRecorder()
+	sse, err := testTools.NewSSEWriter(rr, httptest.NewRequest("GET", "/events", nil))
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	defer sse.Close()
+
+	_ = sse.Send(SSEEvent{Data: "a\rid: evil\r\nevent: x\ny"})
+	_ = sse.Comment("c1\rc2")
+
+	expected := "data: a\ndata: id: evil\ndata: event: x\ndata: y\n\n: c1\n: c2\n\n"
+	if rr.Body.String() != expected {
+		t.Errorf("esperado %q, mas obteve %q", expected, rr.Body.String())
+	}
+
+	for _, ev := range []SSEEvent{{ID: "1\x00"}, {Event: "a\x00b"}, {Event: "a\rb"}} {
+		if err := sse.Send(ev); err == nil {
+			t.Errorf("esperado erro para %+v", ev)
+		}
+	}
+}
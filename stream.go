@@ -0,0 +1,294 @@
+package toolkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// WriteJSONArray escreve os elementos de seq como um array JSON, um a um, sem
+// montar a resposta inteira em memória. A resposta é enviada ao cliente a cada
+// StreamFlushEvery elementos ou StreamFlushInterval, e a escrita é interrompida
+// quando o contexto da requisição é cancelado. Como o status já foi enviado,
+// um erro no meio do stream deixa o array incompleto.
+func WriteJSONArray[T any](t *Tools, w http.ResponseWriter, r *http.Request, status int, seq iter.Seq[T], headers ...http.Header) error {
+	sw := t.newStreamWriter(w, r, status, "application/json", headers...)
+
+	if _, err := w.Write([]byte("[")); err != nil {
+		return err
+	}
+
+	count := 0
+	for v := range seq {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+
+		out, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			out = append([]byte(","), out...)
+		}
+		if err := sw.write(out); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := r.Context().Err(); err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte("]")); err != nil {
+		return err
+	}
+	sw.flush()
+	return nil
+}
+
+// WriteNDJSON escreve os elementos de seq como JSON delimitado por quebras de
+// linha (application/x-ndjson), com o mesmo comportamento de flush e
+// cancelamento de WriteJSONArray.
+func WriteNDJSON[T any](t *Tools, w http.ResponseWriter, r *http.Request, status int, seq iter.Seq[T], headers ...http.Header) error {
+	sw := t.newStreamWriter(w, r, status, "application/x-ndjson", headers...)
+
+	for v := range seq {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+
+		out, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := sw.write(append(out, '\n')); err != nil {
+			return err
+		}
+	}
+	if err := r.Context().Err(); err != nil {
+		return err
+	}
+
+	sw.flush()
+	return nil
+}
+
+// ChanSeq adapta um canal para iter.Seq, permitindo usar WriteJSONArray e
+// WriteNDJSON com produtores baseados em canais. A sequência termina quando o
+// canal é fechado ou ctx é cancelado, mesmo que o canal esteja parado; passe o
+// contexto da requisição (r.Context()).
+func ChanSeq[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// streamWriter escreve elementos de um stream e decide quando enviá-los ao cliente.
+type streamWriter struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	every     int
+	interval  time.Duration
+	pending   int
+	lastFlush time.Time
+}
+
+func (t *Tools) newStreamWriter(w http.ResponseWriter, r *http.Request, status int, contentType string, headers ...http.Header) *streamWriter {
+	if len(headers) > 0 {
+		for key, value := range headers[0] {
+			w.Header()[key] = value
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+
+	sw := &streamWriter{
+		w:         w,
+		rc:        http.NewResponseController(w),
+		every:     100,
+		interval:  time.Second,
+		lastFlush: time.Now(),
+	}
+	if t.StreamFlushEvery > 0 {
+		sw.every = t.StreamFlushEvery
+	}
+	if t.StreamFlushInterval > 0 {
+		sw.interval = t.StreamFlushInterval
+	}
+	return sw
+}
+
+func (sw *streamWriter) write(p []byte) error {
+	if _, err := sw.w.Write(p); err != nil {
+		return err
+	}
+	sw.pending++
+	if sw.pending >= sw.every || time.Since(sw.lastFlush) >= sw.interval {
+		sw.flush()
+	}
+	return nil
+}
+
+// flush envia ao cliente o que já foi escrito, quando o writer suporta flush.
+func (sw *streamWriter) flush() {
+	_ = sw.rc.Flush()
+	sw.pending = 0
+	sw.lastFlush = time.Now()
+}
+
+// ElementError indica a falha de um elemento lido por ReadJSONStream. Index é
+// a posição do elemento (a partir de zero) no array ou no stream NDJSON.
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ReadJSONStream lê um corpo com um array JSON no nível superior, ou com JSON
+// delimitado por quebras de linha (NDJSON), entregando os elementos um a um.
+// Os limites de tamanho, o tratamento de campos desconhecidos, as regras
+// DisallowDuplicateKeys, CaseSensitiveFields e MaxJSONDepth e as mensagens de
+// erro são os mesmos de ReadJSON; cada erro é um *ElementError com o índice do
+// elemento. Erros de tipo, de campo desconhecido, de chave duplicada e de
+// profundidade não interrompem a leitura dos elementos seguintes; os demais
+// encerram a sequência.
+//
+//	for item, err := range toolkit.ReadJSONStream[Item](&tools, w, r) {
+//		if err != nil {
+//			// trata ou interrompe
+//		}
+//	}
+func ReadJSONStream[T any](t *Tools, w http.ResponseWriter, r *http.Request) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		maxBytes := t.maxJSONBytes()
+		if err := t.limitRequestBody(w, r, maxBytes); err != nil {
+			yield(zero, &ElementError{Index: 0, Err: err})
+			return
+		}
+		br := bufio.NewReader(r.Body)
+
+		first, err := peekNonSpace(br)
+		if err != nil {
+			yield(zero, &ElementError{Index: 0, Err: t.jsonDecodeError(err, maxBytes)})
+			return
+		}
+
+		dec := newJSONDecoder(br, t.jsonDecodeOptions())
+
+		isArray := first == '['
+		if isArray {
+			if _, err := dec.Token(); err != nil {
+				yield(zero, &ElementError{Index: 0, Err: t.jsonDecodeError(err, maxBytes)})
+				return
+			}
+		}
+
+		// Elementos de um array estão no segundo nível do corpo para MaxJSONDepth.
+		depth := 1
+		if isArray {
+			depth = 2
+		}
+
+		index := 0
+		for ; dec.More(); index++ {
+			var v T
+			if err := t.decodeStreamElement(dec, &v, depth); err != nil {
+				mapped := err
+				var reqErr *requestError
+				if !errors.As(err, &reqErr) {
+					mapped = t.jsonDecodeError(err, maxBytes)
+				}
+				if !yield(zero, &ElementError{Index: index, Err: mapped}) {
+					return
+				}
+				if errors.Is(mapped, ErrIncorrectJSONType) || errors.Is(mapped, ErrUnknownField) ||
+					errors.Is(mapped, ErrDuplicateJSONKey) || errors.Is(mapped, ErrJSONTooDeep) {
+					continue
+				}
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		// Verifica o fechamento do array e se não há conteúdo após o fim do stream.
+		if isArray {
+			if tok, err := dec.Token(); err != nil || tok != json.Delim(']') {
+				if err == nil || errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+				yield(zero, &ElementError{Index: index, Err: t.jsonDecodeError(err, maxBytes)})
+				return
+			}
+		}
+		var extra json.RawMessage
+		if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+			if err == nil {
+				err = newRequestError(ErrMultipleJSONValues, "body must only contain a single JSON array")
+			} else {
+				err = t.jsonDecodeError(err, maxBytes)
+			}
+			yield(zero, &ElementError{Index: index, Err: err})
+		}
+	}
+}
+
+// decodeStreamElement decodifica o próximo elemento de dec em v. Com
+// DisallowDuplicateKeys, CaseSensitiveFields ou MaxJSONDepth, o elemento é lido
+// primeiro como json.RawMessage e verificado com checkJSON, como em ReadJSON.
+func (t *Tools) decodeStreamElement(dec *json.Decoder, v interface{}, depth int) error {
+	if !t.needsJSONCheck() {
+		return dec.Decode(v)
+	}
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if err := t.checkJSONAt(raw, v, depth); err != nil {
+		return err
+	}
+	return newJSONDecoder(bytes.NewReader(raw), t.jsonDecodeOptions()).Decode(v)
+}
+
+// peekNonSpace retorna o primeiro byte que não é espaço em branco, sem consumi-lo.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c, br.UnreadByte()
+	}
+}
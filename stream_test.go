@@ -0,0 +1,188 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJSONArray(t *testing.T) {
+	var testTools Tools
+
+	type row struct {
+		ID int `json:"id"`
+	}
+
+	testCases := []struct {
+		name     string
+		rows     []row
+		expected string
+	}{
+		{name: "vários elementos", rows: []row{{ID: 1}, {ID: 2}, {ID: 3}}, expected: `[{"id":1},{"id":2},{"id":3}]`},
+		{name: "sem elementos", rows: nil, expected: `[]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/export", nil)
+
+			err := WriteJSONArray(&testTools, rr, req, http.StatusOK, slices.Values(tc.rows))
+			if err != nil {
+				t.Fatalf("erro inesperado recebido: %v", err)
+			}
+			if rr.Body.String() != tc.expected {
+				t.Errorf("corpo incorreto: esperado '%s', mas obteve '%s'", tc.expected, rr.Body.String())
+			}
+			if rr.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Content-Type incorreto: obteve '%s'", rr.Header().Get("Content-Type"))
+			}
+			if !rr.Flushed {
+				t.Error("a resposta deveria ter sido enviada com flush")
+			}
+		})
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	testTools := Tools{StreamFlushEvery: 1}
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= 3; i++ {
+			ch <- i
+		}
+	}()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/export", nil)
+
+	if err := WriteNDJSON(&testTools, rr, req, http.StatusOK, ChanSeq(req.Context(), ch)); err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	if rr.Body.String() != "1\n2\n3\n" {
+		t.Errorf("corpo incorreto: obteve %q", rr.Body.String())
+	}
+	if rr.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("Content-Type incorreto: obteve '%s'", rr.Header().Get("Content-Type"))
+	}
+}
+
+func TestWriteNDJSON_ChanSeqCancelled(t *testing.T) {
+	var testTools Tools
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/export", nil).WithContext(ctx)
+	ch := make(chan int) // nunca recebe valores nem é fechado
+
+	done := make(chan error, 1)
+	go func() {
+		done <- WriteNDJSON(&testTools, httptest.NewRecorder(), req, http.StatusOK, ChanSeq(req.Context(), ch))
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("esperado context.Canceled, mas obteve %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("WriteNDJSON deveria terminar após o cancelamento")
+	}
+}
+
+func TestWriteJSONArray_Cancelled(t *testing.T) {
+	var testTools Tools
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/export", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	produced := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			produced++
+			if i == 2 {
+				cancel()
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	err := WriteJSONArray(&testTools, rr, req, http.StatusOK, seq)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("esperado context.Canceled, mas obteve %v", err)
+	}
+	if produced != 3 {
+		t.Errorf("o stream deveria parar após o cancelamento, mas produziu %d elementos", produced)
+	}
+}
+
+func TestReadJSONStream(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	testCases := []struct {
+		name          string
+		body          string
+		maxSize       int
+		strict        bool
+		expectedNames []string
+		expectedErrs  []string
+	}{
+		{name: "array", body: `[{"name": "a"}, {"name": "b"}]`, expectedNames: []string{"a", "b"}},
+		{name: "array vazio", body: ` [ ] `, expectedNames: nil},
+		{name: "ndjson", body: "{\"name\": \"a\"}\n{\"name\": \"b\"}\n", expectedNames: []string{"a", "b"}},
+		{name: "corpo vazio", body: "", expectedErrs: []string{"element 0: body must not be empty"}},
+		{name: "campo desconhecido continua", body: `[{"name": "a"}, {"nome": "b"}, {"name": "c"}]`, expectedNames: []string{"a", "c"}, expectedErrs: []string{`element 1: body contains unknown field "nome"`}},
+		{name: "tipo incorreto continua", body: "{\"name\": 1}\n{\"name\": \"b\"}", expectedNames: []string{"b"}, expectedErrs: []string{`element 0: body contains incorrect JSON type for field "name"`}},
+		{name: "array não fechado", body: `[{"name": "a"}`, expectedNames: []string{"a"}, expectedErrs: []string{"element 1: body contains badly-formed JSON"}},
+		{name: "conteúdo após o array", body: `[{"name": "a"}] {"name": "b"}`, expectedNames: []string{"a"}, expectedErrs: []string{"element 1: body must only contain a single JSON array"}},
+		{name: "corpo muito grande", body: `[{"name": "a"}, {"name": "uma string muito longa"}]`, maxSize: 20, expectedNames: []string{"a"}, expectedErrs: []string{"element 1: body must not be larger than 20 bytes"}},
+		{name: "chave duplicada continua", body: `[{"name": "a", "name": "b"}, {"name": "c"}]`, strict: true, expectedNames: []string{"c"}, expectedErrs: []string{`element 0: body contains duplicate key "name"`}},
+		{name: "maiúsculas e minúsculas", body: "{\"Name\": \"a\"}\n{\"name\": \"b\"}", strict: true, expectedNames: []string{"b"}, expectedErrs: []string{`element 0: body contains unknown field "Name"`}},
+		{name: "profundidade máxima", body: `[{"name": "a", "x": {"y": 1}}, {"name": "b"}]`, strict: true, expectedNames: []string{"b"}, expectedErrs: []string{"element 0: body must not be nested more than 2 levels"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTools := Tools{MaxJSONSize: tc.maxSize}
+			if tc.strict {
+				testTools.DisallowDuplicateKeys = true
+				testTools.CaseSensitiveFields = true
+				testTools.MaxJSONDepth = 2
+			}
+			req := httptest.NewRequest("POST", "/import", strings.NewReader(tc.body))
+
+			var names, errs []string
+			for v, err := range ReadJSONStream[item](&testTools, httptest.NewRecorder(), req) {
+				if err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+				names = append(names, v.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(tc.expectedNames, ",") {
+				t.Errorf("elementos incorretos: esperado %v, mas obteve %v", tc.expectedNames, names)
+			}
+			if len(errs) != len(tc.expectedErrs) {
+				t.Fatalf("quantidade de erros incorreta: esperado %v, mas obteve %v", tc.expectedErrs, errs)
+			}
+			for i := range errs {
+				if !strings.HasPrefix(errs[i], tc.expectedErrs[i]) {
+					t.Errorf("erro incorreto: esperado '%s', mas obteve '%s'", tc.expectedErrs[i], errs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package toolkit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// tokenBucket implementa um balde de fichas: as fichas são repostas a uma taxa
+// constante até o limite de burst e cada operação consome uma quantidade delas.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // fichas por segundo
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill repõe as fichas acumuladas desde a última chamada. Deve ser chamado com o mutex travado.
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// reserve consome n fichas, mesmo que o saldo fique negativo, e retorna quanto
+// tempo o chamador deve esperar até que a reserva esteja coberta.
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// tryTake consome n fichas apenas se houver saldo suficiente. Caso contrário,
+// não consome nada e retorna o tempo estimado até que o saldo seja suficiente.
+func (b *tokenBucket) tryTake(n float64) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0, true
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// refund devolve fichas de uma reserva que não foi utilizada.
+func (b *tokenBucket) refund(n float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+n)
+}
+
+// wait bloqueia até que n fichas estejam disponíveis ou o contexto seja
+// cancelado, retornando o tempo efetivamente esperado.
+func (b *tokenBucket) wait(ctx context.Context, n float64) (time.Duration, error) {
+	delay := b.reserve(n)
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		b.refund(n)
+		return 0, ctx.Err()
+	}
+}
+
+// throttledWriter limita a velocidade de escrita de um http.ResponseWriter,
+// escrevendo em blocos de no máximo chunk bytes conforme o balde de fichas permite.
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx    context.Context
+	bucket *tokenBucket
+	chunk  int
+}
+
+// newThrottledWriter cria um writer limitado a bytesPerSecond. O tamanho do
+// bloco é limitado a 32 KB para que a taxa seja respeitada mesmo em limites baixos.
+func newThrottledWriter(ctx context.Context, w http.ResponseWriter, bytesPerSecond int) *throttledWriter {
+	chunk := min(bytesPerSecond, 32*1024)
+	return &throttledWriter{
+		ResponseWriter: w,
+		ctx:            ctx,
+		bucket:         newTokenBucket(float64(bytesPerSecond), chunk),
+		chunk:          chunk,
+	}
+}
+
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), tw.chunk)
+		if _, err := tw.bucket.wait(tw.ctx, float64(n)); err != nil {
+			return written, err
+		}
+
+		m, err := tw.ResponseWriter.Write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Flush repassa o flush para o writer original, quando suportado.
+func (tw *throttledWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap permite que http.ResponseController acesse o writer original.
+func (tw *throttledWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// DownloadLimiter limita o número de downloads simultâneos por cliente. Cada
+// chave (por padrão, o IP do cliente) funciona como um semáforo com
+// MaxConcurrent vagas.
+type DownloadLimiter struct {
+	MaxConcurrent int
+	KeyFunc       func(r *http.Request) string
+	RetryAfter    time.Duration
+
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// NewDownloadLimiter cria um DownloadLimiter que permite até maxConcurrent
+// downloads simultâneos por IP de cliente.
+func NewDownloadLimiter(maxConcurrent int) *DownloadLimiter {
+	return &DownloadLimiter{
+		MaxConcurrent: maxConcurrent,
+		RetryAfter:    time.Second,
+	}
+}
+
+// Acquire tenta ocupar uma vaga para a requisição. Em caso de sucesso, retorna
+// uma função que libera a vaga e deve ser chamada ao fim do download.
+func (l *DownloadLimiter) Acquire(r *http.Request) (release func(), ok bool) {
+	key := clientIP(r)
+	if l.KeyFunc != nil {
+		key = l.KeyFunc(r)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		l.active = make(map[string]int)
+	}
+	if l.MaxConcurrent > 0 && l.active[key] >= l.MaxConcurrent {
+		return nil, false
+	}
+	l.active[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.active[key]--
+			if l.active[key] <= 0 {
+				delete(l.active, key)
+			}
+		})
+	}, true
+}
+
+// retryAfterSeconds retorna o valor do cabeçalho Retry-After para respostas 429.
+func (l *DownloadLimiter) retryAfterSeconds() string {
+	seconds := int(math.Ceil(l.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
+
+// clientIP extrai o IP do cliente a partir de r.RemoteAddr.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
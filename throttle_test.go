@@ -0,0 +1,87 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThrottledWriter(t *testing.T) {
+	rr := httptest.NewRecorder()
+	tw := newThrottledWriter(context.Background(), rr, 1000)
+
+	content := bytes.Repeat([]byte("a"), 1500)
+	start := time.Now()
+	n, err := tw.Write(content)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("erro inesperado recebido: %v", err)
+	}
+	if n != len(content) || rr.Body.Len() != len(content) {
+		t.Errorf("bytes escritos incorretos: esperado %d, mas obteve %d", len(content), n)
+	}
+	if elapsed < 400*time.Millisecond {
+		t.Errorf("a escrita deveria ter sido limitada, mas levou apenas %s", elapsed)
+	}
+
+	t.Run("contexto cancelado", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tw := newThrottledWriter(ctx, httptest.NewRecorder(), 10)
+		_, err := tw.Write(bytes.Repeat([]byte("a"), 100))
+		if err == nil {
+			t.Error("um erro era esperado, mas nenhum foi recebido")
+		}
+	})
+}
+
+func TestDownloadLimiter(t *testing.T) {
+	limiter := NewDownloadLimiter(1)
+	req := httptest.NewRequest("GET", "/download", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	release, ok := limiter.Acquire(req)
+	if !ok {
+		t.Fatal("a primeira aquisição deveria ter sucesso")
+	}
+
+	other := httptest.NewRequest("GET", "/download", nil)
+	other.RemoteAddr = "10.0.0.2:1234"
+	releaseOther, ok := limiter.Acquire(other)
+	if !ok {
+		t.Error("clientes diferentes não deveriam compartilhar o limite")
+	}
+	releaseOther()
+
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("conteudo"), 0644); err != nil {
+		t.Fatalf("Falha ao escrever arquivo: %v", err)
+	}
+
+	testTools := Tools{DownloadLimiter: limiter}
+	rr := httptest.NewRecorder()
+	testTools.DownloadStaticFile(rr, req, tempDir, "file.txt", "file.txt")
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("status incorreto: esperado %d, mas obteve %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After incorreto: esperado '1', mas obteve '%s'", rr.Header().Get("Retry-After"))
+	}
+
+	release()
+	release() // liberar duas vezes não deve afetar outras vagas
+
+	rr = httptest.NewRecorder()
+	testTools.DownloadStaticFile(rr, req, tempDir, "file.txt", "file.txt")
+	if rr.Code != http.StatusOK {
+		t.Errorf("status incorreto após liberar a vaga: esperado %d, mas obteve %d", http.StatusOK, rr.Code)
+	}
+}
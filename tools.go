@@ -2,6 +2,7 @@ package toolkit
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -13,6 +14,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const randomStringSource = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_+"
@@ -23,6 +25,37 @@ type Tools struct{
 	AllowedTypes		[]string
 	MaxJSONSize			int
 	AllowUnknownFields	bool
+	UseNumber			bool
+	DisallowDuplicateKeys	bool
+	CaseSensitiveFields	bool
+	MaxJSONDepth		int
+	JSONEngine			JSONEngine
+	CompressDownloads	bool
+	CompressibleTypes	[]string
+	CompressMinSize		int
+	Compressors			map[string]Compressor
+	DownloadRateLimit	int
+	DownloadLimiter		*DownloadLimiter
+	UseProblemDetails	bool
+	ProblemTypeBaseURI	string
+	RemoteTimeout		time.Duration
+	RetryPolicy			*RetryPolicy
+	CircuitBreaker		*CircuitBreaker
+	RateLimiter			*RateLimiter
+	UseRemoteErrors		bool
+	MaxRemoteResponseSize	int
+	RemoteAuthenticator	Authenticator
+	RemoteLogging		*RemoteLogging
+	RemoteTracing		*RemoteTracing
+	Encoders			[]Encoder
+	Decoders			[]Decoder
+	BodyDecompressors	map[string]Decompressor
+	StreamFlushEvery	int
+	StreamFlushInterval	time.Duration
+	SSEHeartbeat		time.Duration
+	DefaultPerPage		int
+	MaxPerPage			int
+	RequestIDHeader		string
 }
 
 // RandomString generates a random string of the specified length n.
@@ -227,69 +260,127 @@ func (t *Tools) DownloadStaticFile(w http.ResponseWriter, r *http.Request, path,
 		f.Close()
 	}
 
+	// Limita os downloads simultâneos por cliente, respondendo 429 quando o limite é excedido.
+	if t.DownloadLimiter != nil {
+		release, ok := t.DownloadLimiter.Acquire(r)
+		if !ok {
+			w.Header().Set("Retry-After", t.DownloadLimiter.retryAfterSeconds())
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		defer release()
+	}
+
+	// Limita a velocidade de download (bytes por segundo) desta conexão.
+	if t.DownloadRateLimit > 0 {
+		w = newThrottledWriter(r.Context(), w, t.DownloadRateLimit)
+	}
+
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", displayName))
 
+	// Serve a versão pré-comprimida (.br/.gz) ou comprimida em tempo real, quando possível.
+	if t.serveCompressedFile(w, r, filePath) {
+		return
+	}
+
 	http.ServeFile(w, r, filePath)
 }
 
 type JSONResponse struct {
-	Error   bool        `json:"error"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Error      bool                   `json:"error"`
+	Message    string                 `json:"message"`
+	Data       interface{}            `json:"data,omitempty"`
+	Pagination *Pagination            `json:"pagination,omitempty"`
+	Links      map[string]string      `json:"links,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Meta       map[string]interface{} `json:"meta,omitempty"`
 }
 
 // WriteJSON efetua a leitura de um JSON, valida se eh um JSON valido,
 // comparando com a interface de destino dos dados, e retorna erros detalhados
-// em caso de falha na leitura ou validação.
+// em caso de falha na leitura ou validação. Corpos com Content-Encoding gzip
+// ou deflate (ou outro encoding registrado em BodyDecompressors) são
+// descomprimidos, e MaxJSONSize limita o tamanho descomprimido.
 func (t *Tools) ReadJSON(w http.ResponseWriter, r *http.Request, data interface{}) error {
-	maxBytes := 1024 * 1024 // 1 MB
-	if t.MaxJSONSize > 0 {
-		maxBytes = t.MaxJSONSize
-	}
-	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
-	dec := json.NewDecoder(r.Body)
-	if !t.AllowUnknownFields {
-		dec.DisallowUnknownFields()
+	maxBytes := t.maxJSONBytes()
+	if err := t.limitRequestBody(w, r, maxBytes); err != nil {
+		return err
 	}
-	err := dec.Decode(data)
-	if err != nil {
-		var syntaxError *json.SyntaxError
-		var unmarshalTypeError *json.UnmarshalTypeError
-		var invalidUnmarshalError *json.InvalidUnmarshalError
-
-		switch {
-			case errors.As(err, &syntaxError):
-				return fmt.Errorf("body contains badly-formed JSON (at character %d)", syntaxError.Offset)
-			case errors.Is(err, io.ErrUnexpectedEOF):
-				return errors.New("body contains badly-formed JSON")
-			case errors.As(err, &unmarshalTypeError):
-				if unmarshalTypeError.Field != "" {
-					return fmt.Errorf("body contains incorrect JSON type for field %q", unmarshalTypeError.Field)
-				}
-				return fmt.Errorf("body contains incorrect JSON type (at character %d)", unmarshalTypeError.Offset)
-			case errors.Is(err, io.EOF):
-				return errors.New("body must not be empty")
-			case strings.HasPrefix(err.Error(), "json: unknown field "):
-				fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
-				return fmt.Errorf("body contains unknown field %s", fieldName)
-			case err.Error() == "http: request body too large":
-				return fmt.Errorf("body must not be larger than %d bytes", maxBytes)
-			case errors.As(err, &invalidUnmarshalError):
-				return fmt.Errorf("internal error: %v", err)
-			default:
-				return err
+
+	var body io.Reader = r.Body
+	if t.needsJSONCheck() {
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			return t.jsonDecodeError(err, maxBytes)
 		}
+		if err := t.checkJSON(buf, data); err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
 	}
-	err = dec.Decode(&struct{}{})
-	if err != io.EOF {
-		return errors.New("body must only contain a single JSON value")
+
+	err := t.jsonEngine().Decode(body, data, t.jsonDecodeOptions())
+	if errors.Is(err, ErrMultipleJSONValues) {
+		return newRequestError(ErrMultipleJSONValues, "body must only contain a single JSON value")
+	}
+	if err != nil {
+		return t.jsonDecodeError(err, maxBytes)
 	}
 	return nil
 }
 
+// maxJSONBytes retorna o tamanho máximo aceito para corpos JSON: MaxJSONSize ou 1 MB.
+func (t *Tools) maxJSONBytes() int {
+	maxBytes := 1024 * 1024 // 1 MB
+	if t.MaxJSONSize > 0 {
+		maxBytes = t.MaxJSONSize
+	}
+	return maxBytes
+}
+
+// jsonDecodeError converte os erros de decodificação de encoding/json nas
+// mensagens amigáveis do toolkit.
+func (t *Tools) jsonDecodeError(err error, maxBytes int) error {
+	if mapped := jsonV2DecodeError(err, maxBytes); mapped != nil {
+		return mapped
+	}
+
+	var syntaxError *json.SyntaxError
+	var unmarshalTypeError *json.UnmarshalTypeError
+	var invalidUnmarshalError *json.InvalidUnmarshalError
+
+	switch {
+		case errors.As(err, &syntaxError):
+			return newRequestError(ErrBadlyFormedJSON, "body contains badly-formed JSON (at character %d)", syntaxError.Offset)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return newRequestError(ErrBadlyFormedJSON, "body contains badly-formed JSON")
+		case errors.As(err, &unmarshalTypeError):
+			if unmarshalTypeError.Field != "" {
+				return newRequestError(ErrIncorrectJSONType, "body contains incorrect JSON type for field %q", unmarshalTypeError.Field)
+			}
+			return newRequestError(ErrIncorrectJSONType, "body contains incorrect JSON type (at character %d)", unmarshalTypeError.Offset)
+		case errors.Is(err, io.EOF):
+			return newRequestError(ErrEmptyBody, "body must not be empty")
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			return newRequestError(ErrUnknownField, "body contains unknown field %s", fieldName)
+		case err.Error() == "http: request body too large":
+			return newRequestError(ErrBodyTooLarge, "body must not be larger than %d bytes", maxBytes)
+		case errors.As(err, &invalidUnmarshalError):
+			return fmt.Errorf("internal error: %v", err)
+		default:
+			return err
+	}
+}
+
 // WriteJSON recebe uma interface e um statusCode, converte para JSON e escreve no response writer.
 func (t *Tools) WriteJSON(w http.ResponseWriter, status int, data interface{}, headers ...http.Header) error {
-	out, err := json.Marshal(data)
+	return t.writeJSONContent(w, status, "application/json", data, headers...)
+}
+
+// writeJSONContent escreve data em JSON com o Content-Type informado.
+func (t *Tools) writeJSONContent(w http.ResponseWriter, status int, contentType string, data interface{}, headers ...http.Header) error {
+	out, err := t.jsonEngine().Marshal(data)
 	if err != nil {
 		return err
 	}
@@ -298,7 +389,7 @@ func (t *Tools) WriteJSON(w http.ResponseWriter, status int, data interface{}, h
 			w.Header()[key] = value
 		}
 	}
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 	_, err = w.Write(out)
 	if err != nil {
@@ -309,8 +400,13 @@ func (t *Tools) WriteJSON(w http.ResponseWriter, status int, data interface{}, h
 
 // ErrorJSON escreve uma mensagem de erro em formato JSON no response writer,
 // com o primeiro status code que for passado na chamada, ou, caso nao seja
-// preenchido, retorna com o status BadRequest.
+// preenchido, retorna com o status BadRequest. Com UseProblemDetails habilitado,
+// a resposta segue o formato application/problem+json (RFC 9457).
 func (t *Tools) ErrorJSON(w http.ResponseWriter, err error, status ...int) error {
+	if t.UseProblemDetails {
+		return t.WriteProblem(w, t.NewProblem(err, status...))
+	}
+
 	statusCode := http.StatusBadRequest
 	if len(status) > 0 {
 		statusCode = status[0]
@@ -324,39 +420,8 @@ func (t *Tools) ErrorJSON(w http.ResponseWriter, err error, status ...int) error
 // PushJSONToRemote envia dados em formato JSON para um endpoint remoto via HTTP POST,
 // utilizando o cliente HTTP fornecido ou o cliente padrão se nenhum for especificado,
 // e retorna a resposta do servidor, o código de status HTTP e qualquer erro encontrado.
+// O cliente padrão usa o timeout RemoteTimeout (30 segundos por padrão); use
+// PushJSONToRemoteContext para cancelar a chamada junto com a requisição de origem.
 func (t *Tools) PushJSONToRemote(uri string, data interface{}, client ...*http.Client) (*JSONResponse, int, error) {
-	// Converte os dados para JSON
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Usa o cliente HTTP fornecido ou cria um novo se nenhum for passado
-	httpClient := &http.Client{}
-	if len(client) > 0 {
-		httpClient = client[0]
-	}
-
-	// Cria a requisição HTTP POST
-	req, err := http.NewRequest("POST", uri, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, 0, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Envia a requisição
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer resp.Body.Close()
-
-	// Lê e retorna a resposta
-	var res JSONResponse
-	err = json.NewDecoder(resp.Body).Decode(&res)
-	if err != nil {
-		return nil, resp.StatusCode, err
-	}
-
-	return &res, resp.StatusCode, nil
+	return t.PushJSONToRemoteContext(context.Background(), uri, data, client...)
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package toolkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// RemoteTracing configura o rastreamento das chamadas remotas feitas pelo
+// toolkit. Cada tentativa de uma chamada é tratada como um span.
+type RemoteTracing struct {
+	// PropagateTraceParent envia o cabeçalho traceparent do W3C Trace
+	// Context. O trace do contexto (definido com WithTraceParent) é
+	// continuado com um novo span id; sem ele, um novo trace é iniciado,
+	// compartilhado por todas as tentativas da chamada.
+	// Um traceparent já definido na requisição é mantido.
+	PropagateTraceParent bool
+
+	// StartSpan, se informado, é chamado antes de cada tentativa. O contexto
+	// retornado é associado à requisição e end é chamado ao receber a
+	// resposta ou o erro. Permite integrar tracers como o OpenTelemetry, que
+	// também podem injetar os seus próprios cabeçalhos em req.
+	StartSpan func(ctx context.Context, req *http.Request) (context.Context, func(resp *http.Response, err error))
+}
+
+type traceParentCtx struct{}
+
+// WithTraceParent retorna um contexto cujas chamadas remotas continuam o trace
+// de traceparent, normalmente o cabeçalho da requisição de origem:
+//
+//	ctx := toolkit.WithTraceParent(r.Context(), r.Header.Get("traceparent"))
+//
+// Valores inválidos são ignorados.
+func WithTraceParent(ctx context.Context, traceparent string) context.Context {
+	if !validTraceParent(traceparent) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceParentCtx{}, strings.ToLower(traceparent))
+}
+
+// startRemoteSpan prepara req para uma tentativa conforme RemoteTracing. Se
+// traceID não for vazio, envia o traceparent, usando traceID quando o contexto
+// não tiver um trace. Retorna a requisição a ser enviada e a função que
+// encerra o span, ou nil.
+func (t *Tools) startRemoteSpan(req *http.Request, traceID string) (*http.Request, func(*http.Response, error)) {
+	tracing := t.RemoteTracing
+	if tracing == nil {
+		return req, nil
+	}
+
+	var end func(*http.Response, error)
+	if tracing.StartSpan != nil {
+		var ctx context.Context
+		ctx, end = tracing.StartSpan(req.Context(), req)
+		if ctx != nil && ctx != req.Context() {
+			req = req.WithContext(ctx)
+		}
+	}
+	if traceID != "" {
+		parent, _ := req.Context().Value(traceParentCtx{}).(string)
+		req.Header.Set("traceparent", childTraceParent(parent, traceID))
+	}
+	return req, end
+}
+
+// childTraceParent retorna um traceparent com o trace id e as flags de parent
+// e um novo span id. Sem parent, usa traceID em um trace amostrado.
+func childTraceParent(parent, traceID string) string {
+	flags := "01"
+	if parent != "" {
+		traceID, flags = parent[3:35], parent[53:55]
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-" + flags
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validTraceParent verifica o formato "00-<trace id>-<span id>-<flags>" da
+// versão 00 do W3C Trace Context, com trace id e span id diferentes de zero.
+func validTraceParent(value string) bool {
+	if len(value) != 55 || value[:3] != "00-" || value[35] != '-' || value[52] != '-' {
+		return false
+	}
+	traceID, spanID, flags := value[3:35], value[36:52], value[53:55]
+	for _, part := range []string{traceID, spanID, flags} {
+		if _, err := hex.DecodeString(part); err != nil {
+			return false
+		}
+	}
+	return strings.Trim(traceID, "0") != "" && strings.Trim(spanID, "0") != ""
+}
@@ -0,0 +1,119 @@
+package toolkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTools_RemoteTracing(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("traceparent"))
+		_, _ = io.WriteString(w, `{"error": false}`)
+	}))
+	defer server.Close()
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("desabilitado por padrão", func(t *testing.T) {
+		received = nil
+		var testTools Tools
+		_, _, _ = testTools.PushJSONToRemoteContext(WithTraceParent(context.Background(), parent), server.URL, nil)
+		if received[0] != "" {
+			t.Errorf("traceparent não deveria ser enviado: %s", received[0])
+		}
+	})
+
+	t.Run("continua o trace do contexto", func(t *testing.T) {
+		received = nil
+		testTools := Tools{RemoteTracing: &RemoteTracing{PropagateTraceParent: true}}
+		_, _, _ = testTools.PushJSONToRemoteContext(WithTraceParent(context.Background(), parent), server.URL, nil)
+		got := received[0]
+		if !validTraceParent(got) || got[3:35] != parent[3:35] || got[36:52] == parent[36:52] || got[53:] != "01" {
+			t.Errorf("traceparent incorreto: %s", got)
+		}
+	})
+
+	t.Run("inicia um novo trace", func(t *testing.T) {
+		received = nil
+		testTools := Tools{RemoteTracing: &RemoteTracing{PropagateTraceParent: true}}
+		_, _, _ = testTools.PushJSONToRemote(server.URL, nil)
+		if !validTraceParent(received[0]) {
+			t.Errorf("traceparent inválido: %s", received[0])
+		}
+	})
+
+	t.Run("tentativas compartilham o novo trace", func(t *testing.T) {
+		var retried []string
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			retried = append(retried, r.Header.Get("traceparent"))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		testTools := Tools{
+			RemoteTracing: &RemoteTracing{PropagateTraceParent: true},
+			RetryPolicy:   &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		}
+		ctx := WithIdempotencyKey(context.Background(), "chave")
+		_, _, _ = testTools.PushJSONToRemoteContext(ctx, failing.URL, nil)
+		if len(retried) != 3 {
+			t.Fatalf("esperadas 3 tentativas, mas obteve %d", len(retried))
+		}
+		for _, got := range retried[1:] {
+			if got[3:35] != retried[0][3:35] || got[36:52] == retried[0][36:52] {
+				t.Errorf("as tentativas deveriam ter o mesmo trace id e span ids diferentes: %v", retried)
+			}
+		}
+	})
+
+	t.Run("StartSpan", func(t *testing.T) {
+		received = nil
+		type spanKey struct{}
+		var ended int
+		testTools := Tools{RemoteTracing: &RemoteTracing{
+			StartSpan: func(ctx context.Context, req *http.Request) (context.Context, func(*http.Response, error)) {
+				req.Header.Set("traceparent", parent)
+				return context.WithValue(ctx, spanKey{}, "span"), func(resp *http.Response, err error) {
+					if resp.Request.Context().Value(spanKey{}) == "span" && err == nil {
+						ended++
+					}
+				}
+			},
+		}}
+		_, _, _ = testTools.PushJSONToRemote(server.URL, nil)
+		if ended != 1 || received[0] != parent {
+			t.Errorf("o span deveria ser iniciado e encerrado: %d %s", ended, received[0])
+		}
+	})
+}
+
+func TestWithTraceParent(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "válido", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", valid: true},
+		{name: "maiúsculas", value: strings.ToUpper("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"), valid: true},
+		{name: "vazio", value: ""},
+		{name: "versão desconhecida", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "trace id zerado", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "span id zerado", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "não hexadecimal", value: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := WithTraceParent(context.Background(), tc.value).Value(traceParentCtx{}).(string)
+			if ok != tc.valid {
+				t.Errorf("esperado %v, mas obteve %v", tc.valid, ok)
+			}
+		})
+	}
+}
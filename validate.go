@@ -0,0 +1,326 @@
+package toolkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validator pode ser implementado pelos tipos decodificados para executar
+// validações próprias, além das regras declaradas na tag `validate`.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError descreve a falha de validação de um campo. Field é um JSON
+// Pointer (RFC 6901) para o campo, como "/items/0/name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors agrega as falhas de validação de todos os campos.
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// ReadJSONAs lê o corpo da requisição com ReadJSON em um novo valor do tipo T
+// e o valida com Validate, retornando o valor decodificado.
+func ReadJSONAs[T any](t *Tools, w http.ResponseWriter, r *http.Request) (T, error) {
+	var data T
+	if err := t.ReadJSON(w, r, &data); err != nil {
+		var zero T
+		return zero, err
+	}
+	if err := t.Validate(&data); err != nil {
+		var zero T
+		return zero, err
+	}
+	return data, nil
+}
+
+// Validate valida data recursivamente usando as regras da tag `validate`
+// (required, min, max, email, oneof e regex) e o método Validate() dos tipos
+// que implementam Validator. Todas as falhas são retornadas juntas em um
+// ValidationErrors. Valores e ponteiros nulos não são validados, e a regra omitempty
+// ignora as regras seguintes quando o campo tem o valor zero.
+//
+//	type Signup struct {
+//		Email string `json:"email" validate:"required,email"`
+//		Age   int    `json:"age" validate:"min=18,max=130"`
+//		Plan  string `json:"plan" validate:"omitempty,oneof=free pro"`
+//		Code  string `json:"code" validate:"regex=^[A-Z]{3}-[0-9]+$"`
+//	}
+//
+// A regra regex deve ser a última da tag, pois consome o restante dela.
+func (t *Tools) Validate(data interface{}) error {
+	return validateWith(data, jsonFieldName)
+}
+
+// fieldNamer retorna o nome público de um campo de struct, usado nos caminhos
+// dos erros, e false se o campo deve ser ignorado.
+type fieldNamer func(sf reflect.StructField) (string, bool)
+
+// validateWith valida data nomeando os campos com namer.
+func validateWith(data interface{}, namer fieldNamer) error {
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(data), "", namer, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+var validatorType = reflect.TypeFor[Validator]()
+
+func validateValue(v reflect.Value, path string, namer fieldNamer, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		// Validate(nil): não há o que validar.
+		return
+	}
+
+	// As regras das tags são verificadas antes do método Validate() do próprio valor.
+	defer callValidator(v, path, errs)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		typ := v.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, ok := namer(sf)
+			if !ok {
+				continue
+			}
+
+			fv := v.Field(i)
+			fieldPath := path
+			if !sf.Anonymous || name != sf.Name {
+				fieldPath = path + "/" + escapeJSONPointer(name)
+			}
+
+			if rules := sf.Tag.Get("validate"); rules != "" {
+				applyRules(fv, rules, fieldPath, errs)
+			}
+			validateValue(fv, fieldPath, namer, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), path+"/"+strconv.Itoa(i), namer, errs)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), path+"/"+escapeJSONPointer(iter.Key().String()), namer, errs)
+		}
+	}
+}
+
+// callValidator executa o método Validate() do valor, se existir, e converte o
+// erro retornado em erros de campo relativos ao caminho atual.
+func callValidator(v reflect.Value, path string, errs *ValidationErrors) {
+	var validator Validator
+	switch {
+	case v.CanAddr() && v.Addr().Type().Implements(validatorType):
+		validator = v.Addr().Interface().(Validator)
+	case v.Type().Implements(validatorType) && v.CanInterface():
+		validator = v.Interface().(Validator)
+	default:
+		return
+	}
+
+	err := validator.Validate()
+	if err == nil {
+		return
+	}
+
+	var verrs ValidationErrors
+	var ferr *FieldError
+	switch {
+	case errors.As(err, &verrs):
+		for _, e := range verrs {
+			*errs = append(*errs, &FieldError{Field: path + e.Field, Rule: e.Rule, Message: e.Message})
+		}
+	case errors.As(err, &ferr):
+		*errs = append(*errs, &FieldError{Field: path + ferr.Field, Rule: ferr.Rule, Message: ferr.Message})
+	default:
+		*errs = append(*errs, &FieldError{Field: path, Rule: "validate", Message: err.Error()})
+	}
+}
+
+// applyRules aplica as regras da tag `validate` ao valor de um campo.
+func applyRules(fv reflect.Value, rules string, path string, errs *ValidationErrors) {
+	parts := strings.Split(rules, ",")
+	for i := 0; i < len(parts); i++ {
+		rule, param, _ := strings.Cut(strings.TrimSpace(parts[i]), "=")
+		if rule == "regex" {
+			// O padrão pode conter vírgulas, então consome o restante da tag.
+			param = strings.Join(append([]string{param}, parts[i+1:]...), ",")
+			i = len(parts)
+		}
+
+		if rule == "required" {
+			if fv.IsZero() {
+				*errs = append(*errs, &FieldError{Field: path, Rule: rule, Message: "is required"})
+				return
+			}
+			continue
+		}
+
+		if rule == "omitempty" {
+			if fv.IsZero() {
+				return
+			}
+			continue
+		}
+
+		v := fv
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+
+		if msg := checkRule(v, rule, param); msg != "" {
+			*errs = append(*errs, &FieldError{Field: path, Rule: rule, Message: msg})
+		}
+	}
+}
+
+// checkRule verifica uma regra e retorna a mensagem de erro, ou "" se o valor for válido.
+func checkRule(v reflect.Value, rule, param string) string {
+	switch rule {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("internal error: invalid %s parameter %q", rule, param)
+		}
+
+		var size float64
+		var unit string
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			size = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			size = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			size = v.Float()
+		case reflect.String:
+			size, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			size, unit = float64(v.Len()), " items"
+		default:
+			return ""
+		}
+
+		if rule == "min" && size < limit {
+			if unit != "" {
+				return fmt.Sprintf("must have at least %s%s", param, unit)
+			}
+			return fmt.Sprintf("must be at least %s", param)
+		}
+		if rule == "max" && size > limit {
+			if unit != "" {
+				return fmt.Sprintf("must have at most %s%s", param, unit)
+			}
+			return fmt.Sprintf("must be at most %s", param)
+		}
+	case "email":
+		if v.Kind() != reflect.String {
+			return ""
+		}
+		addr, err := mail.ParseAddress(v.String())
+		if err != nil || addr.Address != v.String() {
+			return "must be a valid email address"
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		value := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if value == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", strings.Join(options, ", "))
+	case "regex":
+		if v.Kind() != reflect.String {
+			return ""
+		}
+		re, err := compileRule(param)
+		if err != nil {
+			return fmt.Sprintf("internal error: invalid regex %q", param)
+		}
+		if !re.MatchString(v.String()) {
+			return fmt.Sprintf("must match pattern %s", param)
+		}
+	}
+	return ""
+}
+
+// regexCache guarda os padrões já compilados das tags `validate`.
+var regexCache sync.Map
+
+func compileRule(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// jsonFieldName retorna o nome do campo no JSON, conforme a tag `json`, e
+// false se o campo for ignorado ("-").
+func jsonFieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
+
+// escapeJSONPointer escapa um segmento de JSON Pointer conforme a RFC 6901.
+func escapeJSONPointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
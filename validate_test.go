@@ -0,0 +1,128 @@
+package toolkit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"omitempty,regex=^[0-9]{5}(-[0-9]{3})?$"`
+}
+
+type validateSignup struct {
+	Email    string            `json:"email" validate:"required,email"`
+	Age      int               `json:"age" validate:"omitempty,min=18,max=130"`
+	Plan     string            `json:"plan" validate:"omitempty,oneof=free pro"`
+	Name     string            `json:"name" validate:"omitempty,min=2,max=5"`
+	Tags     []string          `json:"tags" validate:"max=2"`
+	Address  *validateAddress  `json:"address"`
+	Contacts []validateAddress `json:"contacts"`
+}
+
+func (s validateSignup) Validate() error {
+	if s.Plan == "pro" && s.Age < 21 {
+		return &FieldError{Field: "/plan", Rule: "custom", Message: "pro plan requires age 21+"}
+	}
+	return nil
+}
+
+func TestTools_Validate(t *testing.T) {
+	var testTools Tools
+
+	testCases := []struct {
+		name           string
+		data           validateSignup
+		expectedFields []string
+	}{
+		{
+			name: "dados válidos",
+			data: validateSignup{Email: "ana@example.com", Age: 30, Plan: "free", Name: "Ana", Address: &validateAddress{City: "SP", Zip: "01001-000"}},
+		},
+		{
+			name:           "campos obrigatórios e formato",
+			data:           validateSignup{Email: "invalido", Age: 10, Plan: "gold", Name: "A"},
+			expectedFields: []string{"/email", "/age", "/plan", "/name"},
+		},
+		{
+			name:           "campos aninhados",
+			data:           validateSignup{Email: "ana@example.com", Address: &validateAddress{Zip: "123"}, Contacts: []validateAddress{{City: "RJ"}, {}}},
+			expectedFields: []string{"/address/city", "/address/zip", "/contacts/1/city"},
+		},
+		{
+			name:           "tamanho de listas e validação customizada",
+			data:           validateSignup{Email: "ana@example.com", Age: 19, Plan: "pro", Tags: []string{"a", "b", "c"}},
+			expectedFields: []string{"/tags", "/plan"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := testTools.Validate(&tc.data)
+
+			if len(tc.expectedFields) == 0 {
+				if err != nil {
+					t.Fatalf("erro inesperado recebido: %v", err)
+				}
+				return
+			}
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("esperado ValidationErrors, mas obteve %v", err)
+			}
+
+			var fields []string
+			for _, e := range verrs {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tc.expectedFields, ",") {
+				t.Errorf("campos com erro incorretos: esperado %v, mas obteve %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestTools_Validate_Nil(t *testing.T) {
+	var testTools Tools
+	var signup *validateSignup
+
+	for _, data := range []interface{}{nil, signup} {
+		if err := testTools.Validate(data); err != nil {
+			t.Errorf("valores nulos não deveriam ser validados, mas obteve %v", err)
+		}
+	}
+}
+
+func TestReadJSONAs(t *testing.T) {
+	var testTools Tools
+
+	t.Run("json válido", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"email": "ana@example.com", "age": 20}`))
+		data, err := ReadJSONAs[validateSignup](&testTools, httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("erro inesperado recebido: %v", err)
+		}
+		if data.Email != "ana@example.com" || data.Age != 20 {
+			t.Errorf("valor decodificado incorreto: %+v", data)
+		}
+	})
+
+	t.Run("json inválido para as regras", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"age": 20}`))
+		_, err := ReadJSONAs[validateSignup](&testTools, httptest.NewRecorder(), req)
+		if err == nil || !strings.Contains(err.Error(), "/email: is required") {
+			t.Errorf("esperado erro de campo obrigatório, mas obteve %v", err)
+		}
+	})
+
+	t.Run("json malformado", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"age": `))
+		_, err := ReadJSONAs[validateSignup](&testTools, httptest.NewRecorder(), req)
+		if err == nil || !strings.Contains(err.Error(), "badly-formed JSON") {
+			t.Errorf("esperado erro de JSON malformado, mas obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package toolkit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultWebhookTolerance é a janela padrão aceita para o timestamp de webhooks assinados.
+const defaultWebhookTolerance = 5 * time.Minute
+
+// WebhookVerifier valida webhooks assinados com HMAC-SHA256 no formato gerado
+// por HMACSigner: a assinatura "sha256=<hex>" de "timestamp.corpo" em
+// SignatureHeader e o timestamp Unix em TimestampHeader.
+type WebhookVerifier struct {
+	// Secrets são os segredos aceitos. Informe mais de um durante a troca de
+	// segredo com o remetente.
+	Secrets [][]byte
+
+	SignatureHeader string
+	TimestampHeader string
+
+	// Tolerance é a diferença máxima entre o timestamp do webhook e o relógio
+	// local (5 minutos por padrão). Webhooks fora da janela são rejeitados,
+	// impedindo a reutilização de requisições capturadas.
+	Tolerance time.Duration
+}
+
+// Verify confere a assinatura de body com os cabeçalhos de r. Retorna um erro
+// que encapsula ErrInvalidSignature se a assinatura não for válida.
+func (v *WebhookVerifier) Verify(r *http.Request, body []byte) error {
+	timestamp := r.Header.Get(headerOrDefault(v.TimestampHeader, defaultTimestampHeader))
+	signature := r.Header.Get(headerOrDefault(v.SignatureHeader, defaultSignatureHeader))
+	if timestamp == "" || signature == "" {
+		return newRequestError(ErrInvalidSignature, "webhook signature is missing")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return newRequestError(ErrInvalidSignature, "webhook timestamp is invalid")
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultWebhookTolerance
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return newRequestError(ErrInvalidSignature, "webhook timestamp is outside the allowed window")
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, defaultSignaturePrefix))
+	if err != nil {
+		return newRequestError(ErrInvalidSignature, "webhook signature is invalid")
+	}
+	for _, secret := range v.Secrets {
+		expected, _ := hex.DecodeString(signPayload(secret, timestamp, body))
+		if hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return newRequestError(ErrInvalidSignature, "webhook signature is invalid")
+}
+
+// VerifyWebhook é um middleware que só repassa para next as requisições com
+// assinatura válida segundo v. O corpo é lido (limitado a MaxJSONSize) e
+// recolocado na requisição para que next possa lê-lo normalmente. Requisições
+// rejeitadas recebem 401 (ou 413, se o corpo for grande demais) via ErrorJSON.
+func (t *Tools) VerifyWebhook(v *WebhookVerifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxBytes := t.maxJSONBytes()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxBytes)))
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				_ = t.ErrorJSON(w, newRequestError(ErrBodyTooLarge, "body must not be larger than %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+				return
+			}
+			_ = t.ErrorJSON(w, newRequestError(ErrMalformedBody, "could not read webhook body: %v", err))
+			return
+		}
+
+		if err := v.Verify(r, body); err != nil {
+			_ = t.ErrorJSON(w, err, http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		next.ServeHTTP(w, r)
+	})
+}
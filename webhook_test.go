@@ -0,0 +1,78 @@
+package toolkit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookVerifier_Verify(t *testing.T) {
+	body := []byte(`{"evento": "pedido.criado"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	testCases := []struct {
+		name          string
+		timestamp     string
+		signature     string
+		secrets       [][]byte
+		expectedError string
+	}{
+		{name: "válido", timestamp: now, signature: "sha256=" + signPayload([]byte("atual"), now, body), secrets: [][]byte{[]byte("atual")}},
+		{name: "segredo anterior durante a troca", timestamp: now, signature: "sha256=" + signPayload([]byte("antigo"), now, body), secrets: [][]byte{[]byte("atual"), []byte("antigo")}},
+		{name: "sem assinatura", timestamp: now, secrets: [][]byte{[]byte("atual")}, expectedError: "webhook signature is missing"},
+		{name: "assinatura incorreta", timestamp: now, signature: "sha256=" + signPayload([]byte("outro"), now, body), secrets: [][]byte{[]byte("atual")}, expectedError: "webhook signature is invalid"},
+		{name: "assinatura não hexadecimal", timestamp: now, signature: "sha256=xyz", secrets: [][]byte{[]byte("atual")}, expectedError: "webhook signature is invalid"},
+		{name: "timestamp inválido", timestamp: "ontem", signature: "sha256=00", secrets: [][]byte{[]byte("atual")}, expectedError: "webhook timestamp is invalid"},
+		{name: "fora da janela", timestamp: old, signature: "sha256=" + signPayload([]byte("atual"), old, body), secrets: [][]byte{[]byte("atual")}, expectedError: "webhook timestamp is outside the allowed window"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("X-Signature-Timestamp", tc.timestamp)
+			if tc.signature != "" {
+				req.Header.Set("X-Signature", tc.signature)
+			}
+
+			v := &WebhookVerifier{Secrets: tc.secrets}
+			err := v.Verify(req, body)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("erro inesperado: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidSignature) || err.Error() != tc.expectedError {
+				t.Errorf("esperado '%s', mas obteve %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestTools_VerifyWebhook(t *testing.T) {
+	testTools := Tools{MaxJSONSize: 64, UseProblemDetails: true}
+	handler := testTools.VerifyWebhook(&WebhookVerifier{Secrets: [][]byte{[]byte("segredo")}}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("sem assinatura", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+		if rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), "invalid-signature") {
+			t.Errorf("esperado 401 com problem details, mas obteve %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("corpo muito grande", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 100))))
+		if rr.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("esperado 413, mas obteve %d", rr.Code)
+		}
+	})
+}